@@ -0,0 +1,133 @@
+package standardchess
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/bitboard"
+	"github.com/elaxer/standardchess/internal/piece"
+)
+
+const (
+	// BackendSquares detects attacked squares by the pseudo moves of the pieces.
+	// It works with boards of any size and pieces of any type and is used by default.
+	BackendSquares Backend = iota
+	// BackendBitboard detects attacked squares by bitboards and precomputed attack tables.
+	// It's much faster, but works only with standard 8x8 boards.
+	// The board falls back to BackendSquares when it contains a piece of a non-standard type.
+	BackendBitboard
+)
+
+var ErrBackendNotSupported = errors.New("backend is not supported")
+
+// Backend is the implementation of the legal move filtering and attacked squares detection of a board.
+// All backends have the same semantics of LegalMoves, IsSquareAttacked, MakeMove and State.
+type Backend uint8
+
+// SetBackend switches the backend of the board created by this package.
+func SetBackend(chessBoard chess.Board, backend Backend) error {
+	b, ok := chessBoard.(*board)
+	if !ok {
+		return fmt.Errorf("%w: unknown board", ErrBackendNotSupported)
+	}
+
+	switch backend {
+	case BackendSquares:
+	case BackendBitboard:
+		if b.squares.EdgePosition() != bitboard.Edge {
+			return fmt.Errorf("%w: the board isn't 8x8", ErrBackendNotSupported)
+		}
+	default:
+		return fmt.Errorf("%w: unknown backend %d", ErrBackendNotSupported, backend)
+	}
+
+	b.backend = backend
+	b.moves = b.moves[:0]
+	b.position = nil
+
+	return nil
+}
+
+// bitboards returns the bitboard representation of the current position.
+// It's built once per position like the legal moves, MakeMove and UndoLastMove reset it.
+// It returns nil if the board doesn't use the bitboard backend
+// or the position can't be represented by bitboards.
+func (b *board) bitboards() *bitboard.Position {
+	if b.position != nil || b.backend != BackendBitboard {
+		return b.position
+	}
+
+	position, ok := bitboard.FromSquares(b.squares)
+	if !ok {
+		return nil
+	}
+	b.position = &position
+
+	return b.position
+}
+
+// squaresBitboards returns the bitboard representation of the squares as they are at the moment.
+// Unlike bitboards, it isn't cached: the move validators call IsSquareAttacked
+// while they move the pieces temporarily.
+func (b *board) squaresBitboards() *bitboard.Position {
+	if b.backend != BackendBitboard {
+		return nil
+	}
+
+	position, ok := bitboard.FromSquares(b.squares)
+	if !ok {
+		return nil
+	}
+
+	return &position
+}
+
+// legalMovesBitboard returns the moves of the piece of the side to move
+// that don't leave its king under attack.
+// En passant captures aren't included.
+func (b *board) legalMovesBitboard(bitboards *bitboard.Position, p chess.Piece, from chess.Position) []chess.Position {
+	fromSquare, _ := bitboard.SquareOf(from)
+	kingSquare, hasKing := bitboards.King(b.turn)
+
+	pseudoMoves := bitboards.PseudoMoves(fromSquare, p.IsMoved())
+
+	legalMoves := make([]chess.Position, 0, bits.OnesCount64(uint64(pseudoMoves)))
+	for to := range pseudoMoves.Squares() {
+		afterMove := *bitboards
+		afterMove.Move(fromSquare, to)
+
+		kingSquare := kingSquare
+		if p.Notation() == piece.NotationKing {
+			kingSquare = to
+		}
+
+		if !hasKing || !afterMove.IsAttacked(kingSquare, !b.turn) {
+			legalMoves = append(legalMoves, to.Position())
+		}
+	}
+
+	return legalMoves
+}
+
+// isSquareAttackedBitboard works like IsSquareAttacked using bitboards.
+func (b *board) isSquareAttackedBitboard(bitboards *bitboard.Position, position chess.Position) bool {
+	square, ok := bitboard.SquareOf(position)
+	if !ok || bitboards.Occupied(!b.turn).Has(square) {
+		return false
+	}
+
+	return bitboards.IsAttacked(square, !b.turn)
+}
+
+func (b Backend) String() string {
+	switch b {
+	case BackendSquares:
+		return "squares"
+	case BackendBitboard:
+		return "bitboard"
+	default:
+		return fmt.Sprintf("Backend(%d)", b)
+	}
+}
@@ -0,0 +1,75 @@
+package standardchess_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/internal/standardtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetBackend(t *testing.T) {
+	board := standardchess.NewBoard()
+
+	require.NoError(t, standardchess.SetBackend(board, standardchess.BackendBitboard))
+	require.NoError(t, standardchess.SetBackend(board, standardchess.BackendSquares))
+	assert.ErrorIs(t, standardchess.SetBackend(board, 100), standardchess.ErrBackendNotSupported)
+}
+
+func TestSetBackend_NotSupported(t *testing.T) {
+	board, err := standardchess.NewBoardEmpty(chess.ColorWhite, nil, chess.NewPosition(chess.FileJ, chess.Rank10))
+	require.NoError(t, err)
+	assert.ErrorIs(t, standardchess.SetBackend(board, standardchess.BackendBitboard), standardchess.ErrBackendNotSupported)
+
+	otherBoard := standardtest.NewBoardEmpty8x8(chess.ColorWhite, nil)
+	assert.NoError(t, standardchess.SetBackend(otherBoard, standardchess.BackendBitboard))
+
+	assert.ErrorIs(t, standardchess.SetBackend(nil, standardchess.BackendBitboard), standardchess.ErrBackendNotSupported)
+}
+
+func TestBackends_SameSemantics(t *testing.T) {
+	fens := []string{
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"8/8/1k6/2b5/2pP4/8/5K2/8 b - d3 0 1",
+		"3k4/3p4/8/K1P4r/8/8/8/8 b - - 0 1",
+		"4k3/8/8/8/8/8/4p3/4K2R w K - 0 1",
+		"R5k1/5ppp/8/8/8/8/8/4K3 b - - 0 1",
+		"7k/8/6Q1/8/8/8/8/K7 b - - 0 1",
+	}
+	for _, fen := range fens {
+		t.Run(fen, func(t *testing.T) {
+			squaresBoard := mustDecodeFEN(t, fen)
+			bitboardBoard := mustDecodeFEN(t, fen)
+			require.NoError(t, standardchess.SetBackend(bitboardBoard, standardchess.BackendBitboard))
+
+			assert.Equal(t, squaresBoard.State(), bitboardBoard.State())
+			assert.ElementsMatch(t, squaresBoard.Moves(), bitboardBoard.Moves())
+
+			for position, p := range squaresBoard.Squares().Iter() {
+				assert.Equal(
+					t,
+					squaresBoard.IsSquareAttacked(position),
+					bitboardBoard.IsSquareAttacked(position),
+					position.String(),
+				)
+
+				if p == nil {
+					continue
+				}
+
+				otherPiece, err := bitboardBoard.Squares().FindByPosition(position)
+				require.NoError(t, err)
+				assert.ElementsMatch(
+					t,
+					squaresBoard.LegalMoves(p),
+					bitboardBoard.LegalMoves(otherPiece),
+					position.String(),
+				)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"slices"
 
 	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/bitboard"
 	"github.com/elaxer/standardchess/internal/move/castling"
 	"github.com/elaxer/standardchess/internal/move/enpassant"
 	"github.com/elaxer/standardchess/internal/move/normal"
@@ -22,6 +23,8 @@ import (
 	"github.com/elaxer/standardchess/internal/mover"
 	"github.com/elaxer/standardchess/internal/piece"
 	"github.com/elaxer/standardchess/internal/rule"
+	"github.com/elaxer/standardchess/internal/setup"
+	"github.com/elaxer/standardchess/internal/zobrist"
 	"github.com/elaxer/standardchess/metric"
 )
 
@@ -46,28 +49,62 @@ var firstRowPieceNotations = [...]string{
 var stateRules = []rule.Rule{
 	rule.Checkmate,
 	rule.Stalemate,
-	rule.Check,
 
+	rule.InsufficientMaterial,
+	rule.ThreefoldRepetition,
 	rule.FiftyMoves,
+
+	rule.Check,
+}
+
+// Setup describes the initial position of a board
+// that cannot be restored from the placement of pieces:
+// the en passant target square, the halfmove clock, the move number and the variant.
+type Setup = setup.Setup
+
+// Board is a chessboard of standard chess.
+// In addition to the chess.Board methods,
+// it describes the initial position and identifies the current one.
+type Board interface {
+	chess.Board
+	// Setup returns the description of the initial position of the board.
+	Setup() Setup
+	// Hash returns the Zobrist hash of the current position.
+	// Positions with the same placement of pieces, side to move,
+	// castling rights and en passant capture availability have the same hash.
+	Hash() uint64
 }
 
 type board struct {
 	turn           chess.Color
 	squares        *chess.Squares
+	setup          Setup
 	moveHistory    []chess.Move
 	capturedPieces []chess.Piece
 	stateRules     []rule.Rule
+	// hashes contains the Zobrist hashes of the positions occurred on the board.
+	// It is filled lazily on the first move, so that pieces may be placed after the board creation.
+	hashes  []uint64
+	backend Backend
 
 	moves []chess.Position
 	state chess.State
+	// position is the bitboard representation of the current position, see board.bitboards.
+	position *bitboard.Position
 }
 
-func NewBoard() chess.Board {
-	board, err := NewBoardEmpty(chess.ColorWhite, nil, EdgePosition)
+func NewBoard() Board {
+	return newBoardWithBackRank(firstRowPieceNotations, setup.Default())
+}
+
+// newBoardWithBackRank creates an 8x8 board with the pieces placed on the back ranks
+// in the given order from the a file and the pawns in front of them.
+func newBoardWithBackRank(notations [8]string, setup Setup) Board {
+	board, err := NewBoardWithSetup(chess.ColorWhite, nil, EdgePosition, setup)
 	must(err)
 
 	squares := board.Squares()
-	for i, notation := range firstRowPieceNotations {
+	for i, notation := range notations {
 		//nolint:gosec
 		file := chess.File(i + 1)
 
@@ -97,7 +134,7 @@ func NewBoard() chess.Board {
 	return board
 }
 
-func NewBoardFromMoves(moves []string) (chess.Board, error) {
+func NewBoardFromMoves(moves []string) (Board, error) {
 	board := NewBoard()
 	for i, move := range moves {
 		if _, err := board.MakeMove(move); err != nil {
@@ -112,7 +149,19 @@ func NewBoardEmpty(
 	turn chess.Color,
 	placement map[chess.Position]chess.Piece,
 	edgePosition chess.Position,
-) (chess.Board, error) {
+) (Board, error) {
+	return NewBoardWithSetup(turn, placement, edgePosition, setup.Default())
+}
+
+// NewBoardWithSetup creates a board in the same way as NewBoardEmpty does,
+// but the game continues from the position described by the setup.
+// Castling rights are taken from the placement: the king and the rook must not be marked as moved.
+func NewBoardWithSetup(
+	turn chess.Color,
+	placement map[chess.Position]chess.Piece,
+	edgePosition chess.Position,
+	setup Setup,
+) (Board, error) {
 	squares, err := chess.SquaresFromPlacement(edgePosition, placement)
 	if err != nil {
 		return nil, err
@@ -121,9 +170,11 @@ func NewBoardEmpty(
 	return &board{
 		turn:           turn,
 		squares:        squares,
+		setup:          setup,
 		moveHistory:    make([]chess.Move, 0, 128),
 		moves:          make([]chess.Position, 0, 64),
 		capturedPieces: make([]chess.Piece, 0, 30),
+		hashes:         make([]uint64, 0, 129),
 
 		stateRules: stateRules,
 	}, nil
@@ -137,6 +188,11 @@ func (b *board) Turn() chess.Color {
 	return b.turn
 }
 
+// Setup returns the description of the initial position of the board.
+func (b *board) Setup() Setup {
+	return b.setup
+}
+
 func (b *board) State() chess.State {
 	if b.state != nil {
 		return b.state
@@ -170,8 +226,13 @@ func (b *board) Moves() []chess.Position {
 
 	uniqueMoves := make(map[chess.Position]bool, 32)
 
-	for piece := range b.squares.GetAllPieces(b.turn) {
-		for _, move := range b.LegalMoves(piece) {
+	bitboards := b.bitboards()
+	for from, piece := range b.squares.Iter() {
+		if piece == nil || piece.Color() != b.turn {
+			continue
+		}
+
+		for _, move := range b.legalMoves(piece, from, bitboards) {
 			uniqueMoves[move] = true
 		}
 	}
@@ -189,20 +250,50 @@ func (b *board) LegalMoves(p chess.Piece) []chess.Position {
 		return make([]chess.Position, 0)
 	}
 
-	pseudoMoves := p.PseudoMoves(from, b.squares)
-
 	if p.Color() != b.Turn() {
-		return pseudoMoves
+		return p.PseudoMoves(from, b.squares)
 	}
 
-	legalMoves := make([]chess.Position, 0, cap(pseudoMoves))
-	for _, to := range pseudoMoves {
-		_ = b.squares.MovePieceTemporarily(from, to, func() {
-			_, kingPosition := b.squares.FindPiece(piece.NotationKing, b.turn)
-			if !b.IsSquareAttacked(kingPosition) {
-				legalMoves = append(legalMoves, to)
+	return b.legalMoves(p, from, b.bitboards())
+}
+
+// IsSquareAttacked reports whether a piece of the opponent of the side to move attacks the position.
+// A position occupied by a piece of the opponent itself isn't considered attacked.
+func (b *board) IsSquareAttacked(position chess.Position) bool {
+	if bitboards := b.squaresBitboards(); bitboards != nil {
+		return b.isSquareAttackedBitboard(bitboards, position)
+	}
+
+	if p, err := b.squares.FindByPosition(position); err != nil || (p != nil && p.Color() != b.turn) {
+		return false
+	}
+
+	for p := range b.squares.GetAllPieces(!b.turn) {
+		from := b.squares.GetByPiece(p)
+		if p.Notation() == piece.NotationPawn {
+			if attacks := piece.PawnAttacks(from, p.Color()); slices.Contains(attacks[:], position) {
+				return true
 			}
-		})
+
+			continue
+		}
+
+		if slices.Contains(p.PseudoMoves(from, b.squares), position) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// legalMoves returns the legal moves of the piece of the side to move.
+// The bitboards are nil if the bitboard backend isn't used.
+func (b *board) legalMoves(p chess.Piece, from chess.Position, bitboards *bitboard.Position) []chess.Position {
+	var legalMoves []chess.Position
+	if bitboards != nil {
+		legalMoves = b.legalMovesBitboard(bitboards, p, from)
+	} else {
+		legalMoves = b.legalMovesSquares(from, p.PseudoMoves(from, b.squares))
 	}
 
 	enPassantPosition := enpassant.EnPassantTargetSquare(b)
@@ -213,15 +304,18 @@ func (b *board) LegalMoves(p chess.Piece) []chess.Position {
 	return legalMoves
 }
 
-func (b *board) IsSquareAttacked(position chess.Position) bool {
-	for piece := range b.squares.GetAllPieces(!b.turn) {
-		from := b.squares.GetByPiece(piece)
-		if slices.Contains(piece.PseudoMoves(from, b.squares), position) {
-			return true
-		}
+func (b *board) legalMovesSquares(from chess.Position, pseudoMoves []chess.Position) []chess.Position {
+	legalMoves := make([]chess.Position, 0, len(pseudoMoves))
+	for _, to := range pseudoMoves {
+		_ = b.squares.MovePieceTemporarily(from, to, func() {
+			_, kingPosition := b.squares.FindPiece(piece.NotationKing, b.turn)
+			if !b.IsSquareAttacked(kingPosition) {
+				legalMoves = append(legalMoves, to)
+			}
+		})
 	}
 
-	return false
+	return legalMoves
 }
 
 func (b *board) MakeMove(move string) (chess.Move, error) {
@@ -229,6 +323,20 @@ func (b *board) MakeMove(move string) (chess.Move, error) {
 		return nil, ErrCannotMoveInTerminalState
 	}
 
+	moveResult, err := b.makeMove(move)
+	if err != nil {
+		return nil, err
+	}
+	moveResult.SetBoardNewState(b.State())
+
+	return moveResult, nil
+}
+
+// makeMove makes the move without checking the state of the board.
+// The new state of the move is left clear, computing it is the most expensive part of a move.
+func (b *board) makeMove(move string) (chess.Move, error) {
+	hash, rights := b.Hash(), zobrist.Rights(b)
+
 	moveResult, err := mover.MakeMove(move, b)
 	if err != nil {
 		return nil, err
@@ -240,10 +348,21 @@ func (b *board) MakeMove(move string) (chess.Move, error) {
 		b.capturedPieces = append(b.capturedPieces, moveResult.CapturedPiece())
 	}
 
+	if len(b.hashes) == 0 {
+		b.hashes = append(b.hashes, hash)
+	}
+	if moveHash, ok := zobrist.Move(moveResult); ok {
+		hash ^= rights ^ moveHash ^ zobrist.Turn ^ zobrist.Rights(b)
+	} else {
+		hash = zobrist.Hash(b)
+	}
+	b.hashes = append(b.hashes, hash)
+
 	b.moves = b.moves[:0]
 	b.state = nil
+	b.position = nil
 
-	moveResult.SetBoardNewState(b.State())
+	moveResult.SetBoardNewState(chess.StateClear)
 
 	return moveResult, nil
 }
@@ -266,12 +385,39 @@ func (b *board) UndoLastMove() (chess.Move, error) {
 		_ = slices.Delete(b.capturedPieces, len(b.capturedPieces)-1, len(b.capturedPieces))
 	}
 
+	b.hashes = b.hashes[:len(b.hashes)-1]
+	if len(b.moveHistory) == 0 {
+		b.hashes = b.hashes[:0]
+	}
+
 	b.moves = b.moves[:0]
 	b.state = nil
+	b.position = nil
 
 	return lastMove, nil
 }
 
+// Hash returns the Zobrist hash of the current position.
+// The hash is updated incrementally by MakeMove and UndoLastMove.
+// Changes of the squares made bypassing these methods are taken into account only until the first move.
+func (b *board) Hash() uint64 {
+	if len(b.hashes) == 0 {
+		return zobrist.Hash(b)
+	}
+
+	return b.hashes[len(b.hashes)-1]
+}
+
+// PositionHashes returns the hashes of all positions occurred on the board in chronological order.
+// The last hash belongs to the current position.
+func (b *board) PositionHashes() []uint64 {
+	if len(b.hashes) == 0 {
+		return []uint64{b.Hash()}
+	}
+
+	return b.hashes
+}
+
 func (b *board) MarshalJSON() ([]byte, error) {
 	type Placement struct {
 		Piece      chess.Piece      `json:"piece"`
@@ -319,12 +465,12 @@ func (b *board) MarshalJSON() ([]byte, error) {
 			lastMovements = append(
 				lastMovements,
 				map[string]string{
-					"from": castling.KingInitPosition(move.Side()).String(),
-					"to":   castling.KingCastledPosition(move.CastlingType, move.Side()).String(),
+					"from": move.InitKingPosition.String(),
+					"to":   move.KingNewPosition().String(),
 				},
 				map[string]string{
-					"from": castling.RookInitPosition(move.CastlingType, move.Side()).String(),
-					"to":   castling.RookCastledPosition(move.CastlingType, move.Side()).String(),
+					"from": move.InitRookPosition.String(),
+					"to":   move.RookNewPosition().String(),
 				},
 			)
 		}
@@ -3,10 +3,12 @@ package standardchess_test
 import (
 	"testing"
 
+	"github.com/elaxer/chess"
 	"github.com/elaxer/standardchess"
 	"github.com/elaxer/standardchess/encoding/fen"
 	"github.com/elaxer/standardchess/encoding/pgn"
 	"github.com/elaxer/standardchess/internal/standardtest"
+	"github.com/elaxer/standardchess/internal/zobrist"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -185,3 +187,206 @@ Kxb8 Qc6 53. Ka7 Kd7 54. Kb8 Qc7+ 55. Ka8 Kc6 1/2-1/2`,
 		b.StartTimer()
 	}
 }
+
+func TestBoard_Hash(t *testing.T) {
+	tests := []struct {
+		name  string
+		board standardchess.Board
+		moves []string
+	}{
+		{
+			"castlings_and_promotions",
+			standardchess.NewBoard(),
+			[]string{
+				"e4", "d5", "exd5", "Nf6", "Bb5+", "c6", "dxc6", "Qd6", "cxb7+", "Nc6", "bxa8=Q",
+				"e5", "Nf3", "Be7", "O-O", "O-O", "Qxc8", "Rxc8", "Bxc6", "Qxc6",
+			},
+		},
+		{
+			"en_passant",
+			standardchess.NewBoard(),
+			[]string{"e4", "Nf6", "e5", "d5", "exd6", "exd6", "d4", "Qe7+", "Be2", "Qxe2+"},
+		},
+		{
+			"decoded",
+			mustDecodeFEN(t, "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1"),
+			[]string{"O-O-O", "O-O", "a4", "bxa3", "Nxf7", "Rxf7", "d6", "hxg2", "dxe7", "gxh1=Q"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hashes := []uint64{tt.board.Hash()}
+			for _, move := range tt.moves {
+				_, err := tt.board.MakeMove(move)
+				require.NoError(t, err, move)
+				require.Equal(t, zobrist.Hash(tt.board), tt.board.Hash(), move)
+
+				hashes = append(hashes, tt.board.Hash())
+			}
+
+			for i := len(tt.moves) - 1; i >= 0; i-- {
+				_, err := tt.board.UndoLastMove()
+				require.NoError(t, err)
+				require.Equal(t, hashes[i], tt.board.Hash(), tt.moves[i])
+			}
+		})
+	}
+}
+
+func TestBoard_Hash_Transposition(t *testing.T) {
+	board1, err := standardchess.NewBoardFromMoves([]string{"Nf3", "Nf6", "Nc3", "e5", "e4"})
+	require.NoError(t, err)
+	board2, err := standardchess.NewBoardFromMoves([]string{"e4", "e5", "Nc3", "Nf6", "Nf3"})
+	require.NoError(t, err)
+	assert.Equal(t, board1.Hash(), board2.Hash())
+
+	board3, err := standardchess.NewBoardFromMoves([]string{"Nc3", "e5", "e4", "Nf6", "Nf3"})
+	require.NoError(t, err)
+	assert.Equal(t, board1.Hash(), board3.Hash())
+
+	board4, err := standardchess.NewBoardFromMoves([]string{"Nf3", "Nf6", "Nc3", "e6", "e4", "e5"})
+	require.NoError(t, err)
+	assert.NotEqual(t, board1.Hash(), board4.Hash(), "the side to move differs")
+}
+
+func TestBoard_Hash_Rights(t *testing.T) {
+	board := standardchess.NewBoard()
+	initHash := board.Hash()
+	for _, move := range []string{"Nf3", "Nf6", "Rg1", "Rg8", "Rh1", "Rh8", "Ng1", "Ng8"} {
+		_, err := board.MakeMove(move)
+		require.NoError(t, err, move)
+	}
+	assert.NotEqual(t, initHash, board.Hash(), "castling rights are lost")
+
+	withEnPassant := mustDecodeFEN(t, "4k3/8/8/8/3pP3/8/8/4K3 b - e3 0 1")
+	withoutEnPassant := mustDecodeFEN(t, "4k3/8/8/8/3pP3/8/8/4K3 b - - 0 1")
+	assert.NotEqual(t, withEnPassant.Hash(), withoutEnPassant.Hash())
+
+	noCapture := mustDecodeFEN(t, "4k3/8/8/8/p3P3/8/8/4K3 b - e3 0 1")
+	noCaptureWithoutEnPassant := mustDecodeFEN(t, "4k3/8/8/8/p3P3/8/8/4K3 b - - 0 1")
+	assert.Equal(t, noCapture.Hash(), noCaptureWithoutEnPassant.Hash())
+}
+
+func mustDecodeFEN(t *testing.T, str string) standardchess.Board {
+	t.Helper()
+
+	board, err := fen.Decode(str)
+	require.NoError(t, err)
+
+	return board
+}
+
+func TestBoard_MakeMove_UCI(t *testing.T) {
+	tests := []struct {
+		name    string
+		fen     string
+		move    string
+		wantSAN string
+		wantFEN string
+	}{
+		{
+			"pawn",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			"e2e4",
+			"e4",
+			"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		},
+		{
+			"knight",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			"g1f3",
+			"Nf3",
+			"rnbqkbnr/pppppppp/8/8/8/5N2/PPPPPPPP/RNBQKB1R b KQkq - 1 1",
+		},
+		{
+			"disambiguation",
+			"4k3/8/8/8/1N3N2/8/8/4K3 w - - 0 1",
+			"f4d3",
+			"Nfd3",
+			"4k3/8/8/8/1N6/3N4/8/4K3 b - - 1 1",
+		},
+		{
+			"promotion",
+			"3r2k1/4P3/8/8/8/8/8/4K3 w - - 0 1",
+			"e7d8n",
+			"exd8=N",
+			"3N2k1/8/8/8/8/8/8/4K3 b - - 0 1",
+		},
+		{
+			"en_passant",
+			"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 2",
+			"e5d6",
+			"exd6",
+			"4k3/8/3P4/8/8/8/8/4K3 b - - 0 2",
+		},
+		{
+			"short_castling",
+			"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1",
+			"e1g1",
+			"O-O",
+			"r3k2r/8/8/8/8/8/8/R4RK1 b kq - 1 1",
+		},
+		{
+			"long_castling",
+			"r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1",
+			"e8c8",
+			"O-O-O",
+			"2kr3r/8/8/8/8/8/8/R3K2R w KQ - 1 2",
+		},
+		{
+			"castling_king_to_rook",
+			"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1",
+			"e1h1",
+			"O-O",
+			"r3k2r/8/8/8/8/8/8/R4RK1 b kq - 1 1",
+		},
+		{
+			"chess960_castling",
+			"bqnbr1kr/pppppppp/4n3/8/8/4N3/PPPPPPPP/BQNBR1KR w KQkq - 2 2",
+			"g1h1",
+			"O-O",
+			"bqnbr1kr/pppppppp/4n3/8/8/4N3/PPPPPPPP/BQNBRRK1 b kq - 3 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := mustDecodeFEN(t, tt.fen)
+
+			result, err := board.MakeMove(tt.move)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantSAN, result.String())
+			assert.Equal(t, tt.wantFEN, fen.Encode(board).String())
+		})
+	}
+}
+
+func TestBoard_MakeMove_UCIInvalid(t *testing.T) {
+	for _, move := range []string{"e3e4", "e2e5", "e1g1", "g1f3q", "e7e8"} {
+		t.Run(move, func(t *testing.T) {
+			board := standardchess.NewBoard()
+
+			_, err := board.MakeMove(move)
+			assert.Error(t, err)
+			assert.Empty(t, board.MoveHistory())
+		})
+	}
+}
+
+func BenchmarkBoard_Moves(b *testing.B) {
+	for _, backend := range []standardchess.Backend{standardchess.BackendSquares, standardchess.BackendBitboard} {
+		b.Run(backend.String(), func(b *testing.B) {
+			board, err := fen.Decode("r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+			require.NoError(b, err)
+			require.NoError(b, standardchess.SetBackend(board, backend))
+
+			b.ResetTimer()
+			for range b.N {
+				for p := range board.Squares().GetAllPieces(board.Turn()) {
+					board.LegalMoves(p)
+				}
+				board.IsSquareAttacked(chess.PositionFromString("e1"))
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package standardchess
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/elaxer/standardchess/internal/piece"
+	"github.com/elaxer/standardchess/internal/setup"
+)
+
+const (
+	// Chess960PositionsCount is the number of the initial positions of Chess960.
+	Chess960PositionsCount = 960
+	// Chess960StandardID is the identifier of the Chess960 initial position
+	// which is the same as the standard one.
+	Chess960StandardID = 518
+)
+
+var ErrInvalidChess960ID = errors.New("invalid Chess960 position identifier")
+
+// chess960Knights contains the placements of the knights on the five squares
+// left after placing the bishops and the queen.
+var chess960Knights = [10][2]int{
+	{0, 1}, {0, 2}, {0, 3}, {0, 4},
+	{1, 2}, {1, 3}, {1, 4},
+	{2, 3}, {2, 4},
+	{3, 4},
+}
+
+// NewBoard960 creates a board with the Chess960 initial position with the identifier from 0 to 959
+// according to the Scharnagl numbering.
+// The position 518 is the standard initial position.
+func NewBoard960(id int) (Board, error) {
+	notations, err := chess960BackRank(id)
+	if err != nil {
+		return nil, err
+	}
+
+	boardSetup := setup.Default()
+	boardSetup.Chess960 = true
+
+	return newBoardWithBackRank(notations, boardSetup), nil
+}
+
+// chess960BackRank returns the placement of the pieces on the back rank of the Chess960 position.
+func chess960BackRank(id int) ([8]string, error) {
+	var notations [8]string
+	if id < 0 || id >= Chess960PositionsCount {
+		return notations, fmt.Errorf("%w: %d", ErrInvalidChess960ID, id)
+	}
+
+	n := id
+	notations[2*(n%4)+1] = piece.NotationBishop
+	n /= 4
+	notations[2*(n%4)] = piece.NotationBishop
+	n /= 4
+	placeOnEmpty(&notations, n%6, piece.NotationQueen)
+	n /= 6
+
+	knights := chess960Knights[n]
+	// The second knight is placed first, so that the index of the first one is not shifted.
+	placeOnEmpty(&notations, knights[1], piece.NotationKnight)
+	placeOnEmpty(&notations, knights[0], piece.NotationKnight)
+
+	placeOnEmpty(&notations, 0, piece.NotationRook)
+	placeOnEmpty(&notations, 0, piece.NotationKing)
+	placeOnEmpty(&notations, 0, piece.NotationRook)
+
+	return notations, nil
+}
+
+// placeOnEmpty places the piece on the empty square with the index among the empty squares.
+func placeOnEmpty(notations *[8]string, index int, notation string) {
+	for i, n := range notations {
+		if n != "" {
+			continue
+		}
+		if index == 0 {
+			notations[i] = notation
+
+			return
+		}
+
+		index--
+	}
+}
@@ -0,0 +1,133 @@
+package standardchess_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBoard960(t *testing.T) {
+	tests := []struct {
+		id      int
+		wantFEN string
+	}{
+		{0, "bbqnnrkr/pppppppp/8/8/8/8/PPPPPPPP/BBQNNRKR w KQkq - 0 1"},
+		{518, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"},
+		{959, "rkrnnqbb/pppppppp/8/8/8/8/PPPPPPPP/RKRNNQBB w KQkq - 0 1"},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.id), func(t *testing.T) {
+			board, err := standardchess.NewBoard960(tt.id)
+			require.NoError(t, err)
+
+			assert.True(t, board.Setup().Chess960)
+			assert.Equal(t, tt.wantFEN, fen.Encode(board).String())
+		})
+	}
+}
+
+func TestNewBoard960_AllPositions(t *testing.T) {
+	placements := make(map[string]bool, standardchess.Chess960PositionsCount)
+	for id := range standardchess.Chess960PositionsCount {
+		board, err := standardchess.NewBoard960(id)
+		require.NoError(t, err)
+
+		placement := fen.Encode(board).Placement()
+		placements[placement] = true
+
+		backRank := placement[strings.LastIndex(placement, "/")+1:]
+		assert.Regexp(t, "^[^K]*R[^R]*K[^R]*R[^K]*$", backRank)
+		assert.NotEqual(t, strings.Index(backRank, "B")%2, strings.LastIndex(backRank, "B")%2, backRank)
+	}
+
+	assert.Len(t, placements, standardchess.Chess960PositionsCount)
+}
+
+func TestNewBoard960_InvalidID(t *testing.T) {
+	for _, id := range []int{-1, standardchess.Chess960PositionsCount} {
+		_, err := standardchess.NewBoard960(id)
+		assert.ErrorIs(t, err, standardchess.ErrInvalidChess960ID)
+	}
+}
+
+func TestBoard960_Castling(t *testing.T) {
+	tests := []struct {
+		name    string
+		fen     string
+		move    string
+		wantFEN string
+	}{
+		{
+			"king_stays",
+			"bqnbr1kr/pppppppp/4n3/8/8/4N3/PPPPPPPP/BQNBR1KR w KQkq - 2 2",
+			"O-O",
+			"bqnbr1kr/pppppppp/4n3/8/8/4N3/PPPPPPPP/BQNBRRK1 b kq - 3 2",
+		},
+		{
+			"king_and_rook_swap",
+			"4k3/8/8/8/8/8/8/1RK5 w Q - 0 1",
+			"O-O-O",
+			"4k3/8/8/8/8/8/8/2KR4 b - - 1 1",
+		},
+		{
+			"inner_rook",
+			"4k3/8/8/8/8/8/8/R1R1K3 w C - 0 1",
+			"O-O-O",
+			"4k3/8/8/8/8/8/8/R1KR4 b - - 1 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := mustDecodeFEN(t, tt.fen)
+			require.True(t, board.Setup().Chess960)
+
+			_, err := board.MakeMove(tt.move)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFEN, fen.Encode(board).String())
+
+			_, err = board.UndoLastMove()
+			require.NoError(t, err)
+			assert.Equal(t, tt.fen, fen.Encode(board).String())
+		})
+	}
+}
+
+func TestBoard960_CastlingIllegal(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		move string
+	}{
+		{"rook_destination_occupied", "4k3/8/8/8/8/8/8/5NKR w H - 0 1", "O-O"},
+		{"rook_shields_king_destination", "4k3/8/8/8/8/8/8/rR1K4 w B - 0 1", "O-O-O"},
+		{"king_passes_attacked_square", "4k3/8/8/8/8/8/5r2/1K5R w H - 0 1", "O-O"},
+		{"king_two_squares_is_not_castling", "4k3/8/8/8/8/8/8/R3K2R w KQ - 0 1", "e1g1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := fen.DecodeChess960(tt.fen)
+			require.NoError(t, err)
+
+			_, err = board.MakeMove(tt.move)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMoveList_Chess960(t *testing.T) {
+	board := mustDecodeFEN(t, "bqnbr1kr/pppppppp/4n3/8/8/4N3/PPPPPPPP/BQNBR1KR w KQkq - 2 2")
+
+	var castlings []string
+	for _, move := range standardchess.MoveList(board) {
+		if move.IsCastling {
+			castlings = append(castlings, move.UCI()+" "+move.SAN())
+		}
+	}
+
+	assert.Equal(t, []string{"g1h1 O-O"}, castlings)
+}
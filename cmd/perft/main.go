@@ -0,0 +1,76 @@
+// Perft counts the leaf nodes of the legal move tree of a position to verify the move generation.
+//
+// Usage:
+//
+//	perft [-fen FEN] [-divide] [-bitboard] depth
+//
+// With -divide the number of leaf nodes is also printed for each legal move in the UCI notation.
+// With -bitboard the board uses the bitboard backend.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+)
+
+func main() {
+	fenStr := flag.String("fen", "", "position to count, the initial position by default")
+	divide := flag.Bool("divide", false, "print the number of leaf nodes for each move")
+	useBitboard := flag.Bool("bitboard", false, "use the bitboard backend")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-fen FEN] [-divide] [-bitboard] depth\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	depth, err := strconv.Atoi(flag.Arg(0))
+	if err != nil || depth < 0 {
+		fmt.Fprintf(os.Stderr, "invalid depth %q\n", flag.Arg(0))
+		os.Exit(2)
+	}
+
+	board := standardchess.NewBoard()
+	if *fenStr != "" {
+		if board, err = fen.Decode(*fenStr); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if *useBitboard {
+		if err := standardchess.SetBackend(board, standardchess.BackendBitboard); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	start := time.Now()
+
+	var nodes int
+	if *divide {
+		moves := standardchess.PerftDivide(board, depth)
+		for _, move := range slices.Sorted(maps.Keys(moves)) {
+			fmt.Printf("%s: %d\n", move, moves[move])
+			nodes += moves[move]
+		}
+		fmt.Println()
+	} else {
+		nodes = standardchess.Perft(board, depth)
+	}
+
+	fmt.Printf("Nodes searched: %d\n", nodes)
+	fmt.Printf("Time: %s\n", time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,21 @@
+// Standardchess-uci runs the built-in engine speaking the Universal Chess Interface protocol on stdin and stdout,
+// so it can be hosted by any UCI chess GUI or bot, e.g. Arena, Cute Chess or lichess-bot.
+//
+// Usage:
+//
+//	standardchess-uci
+//
+// The engine supports the Hash, Ponder and UCI_Chess960 options, pondering and infinite analysis.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := newServer(os.Stdout).run(os.Stdin); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/engine"
+	"github.com/elaxer/standardchess/internal/protocol"
+)
+
+const (
+	engineName   = "standardchess"
+	engineAuthor = "elaxer"
+)
+
+const (
+	// hashEntrySize is the approximate size of an entry of the transposition table in bytes.
+	hashEntrySize = 40
+	// defaultHash and maxHash are the default and the maximum sizes of the transposition table in megabytes.
+	defaultHash = engine.DefaultHashSize * hashEntrySize >> 20
+	maxHash     = 4096
+)
+
+var (
+	errUnknownPosition = errors.New("unknown position")
+	errIllegalMove     = errors.New("illegal move")
+)
+
+// server is the UCI engine reading the commands of the GUI and writing the answers.
+type server struct {
+	mu  sync.Mutex
+	out io.Writer
+
+	engine *engine.Engine
+	// board is nil if the last position command has failed, the GUI and the engine disagree on the position then.
+	board    standardchess.Board
+	chess960 bool
+	search   *search
+}
+
+// search is the running search.
+type search struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	// release is closed when the best move may be sent: at once for the usual searches,
+	// on stop for the infinite ones and on stop or ponderhit for the pondering ones.
+	release  chan struct{}
+	released bool
+	// budget is the time given to the search, it starts on ponderhit if the engine ponders.
+	budget time.Duration
+	timer  *time.Timer
+}
+
+// goParams are the arguments of the go command.
+type goParams struct {
+	limits   engine.Limits
+	budget   time.Duration
+	infinite bool
+	ponder   bool
+}
+
+func newServer(out io.Writer) *server {
+	s := &server{out: out}
+	s.newEngine(defaultHash)
+	s.board = protocol.SearchBoard(standardchess.NewBoard())
+
+	return s
+}
+
+// run handles the commands read from r until the quit command or the end of the input.
+func (s *server) run(r io.Reader) error {
+	defer s.stop()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		command, args := protocol.CutCommand(scanner.Text())
+		if command == "quit" {
+			return nil
+		}
+
+		s.handle(command, args)
+	}
+
+	return scanner.Err()
+}
+
+// handle handles the command, the unknown commands are ignored.
+func (s *server) handle(command, args string) {
+	switch command {
+	case "uci":
+		s.send("id name " + engineName)
+		s.send("id author " + engineAuthor)
+		s.send(fmt.Sprintf("option name Hash type spin default %d min 1 max %d", defaultHash, maxHash))
+		s.send("option name Ponder type check default false")
+		s.send("option name UCI_Chess960 type check default false")
+		s.send("uciok")
+	case "isready":
+		s.send("readyok")
+	case "setoption":
+		s.stop()
+		s.setOption(args)
+	case "ucinewgame":
+		s.stop()
+		s.engine.Clear()
+		s.board = protocol.SearchBoard(standardchess.NewBoard())
+	case "position":
+		s.stop()
+		if err := s.position(args); err != nil {
+			s.board = nil
+			s.send("info string " + err.Error())
+		}
+	case "go":
+		s.stop()
+		if s.board == nil {
+			s.send("bestmove (none)")
+
+			return
+		}
+		s.start(parseGo(args, s.board.Turn()))
+	case "stop":
+		s.stop()
+	case "ponderhit":
+		s.ponderHit()
+	}
+}
+
+func (s *server) newEngine(hash int) {
+	s.engine = engine.New(hash << 20 / hashEntrySize)
+	s.engine.Progress = s.info
+}
+
+// setOption handles the arguments of the setoption command, e.g. "name Hash value 64".
+func (s *server) setOption(args string) {
+	name, value, _ := strings.Cut(strings.TrimPrefix(args, "name "), " value ")
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "hash":
+		if hash, err := strconv.Atoi(value); err == nil && hash > 0 && hash <= maxHash {
+			s.newEngine(hash)
+		}
+	case "uci_chess960":
+		s.chess960 = value == "true"
+	}
+}
+
+// position sets the board by the arguments of the position command,
+// e.g. "startpos moves e2e4 e7e5" or "fen 8/8/8/8/8/8/8/K1k5 w - - 0 1".
+// The moves are made through the unclaimed draws, the GUI may go on after a threefold repetition.
+func (s *server) position(args string) error {
+	setup, moves, _ := strings.Cut(args, "moves")
+
+	var board standardchess.Board
+	switch kind, f := protocol.CutCommand(setup); kind {
+	case "startpos":
+		board = standardchess.NewBoard()
+	case "fen":
+		var err error
+		if s.chess960 {
+			board, err = fen.DecodeChess960(f)
+		} else {
+			board, err = fen.Decode(f)
+		}
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: %s", errUnknownPosition, strings.TrimSpace(setup))
+	}
+
+	board = protocol.SearchBoard(board)
+	for _, move := range strings.Fields(moves) {
+		if _, err := standardchess.MakeMovePastDraw(board, move); err != nil {
+			return fmt.Errorf("%w: %s: %w", errIllegalMove, move, err)
+		}
+	}
+	s.board = board
+
+	return nil
+}
+
+// start starts the search on the board, the best move is sent when the search is finished and released.
+func (s *server) start(params goParams) {
+	ctx, cancel := context.WithCancel(context.Background())
+	current := &search{
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		release: make(chan struct{}),
+		budget:  params.budget,
+	}
+	if !params.infinite && !params.ponder {
+		current.startClock()
+		current.releaseResult()
+	}
+	s.search = current
+
+	board, e := s.board, s.engine
+	go func() {
+		defer close(current.done)
+
+		result, err := e.Search(ctx, board, params.limits)
+		<-current.release
+		cancel()
+
+		if err != nil {
+			s.send("bestmove (none)")
+
+			return
+		}
+		s.send(bestMove(result))
+	}()
+}
+
+// stop stops the search if it's running and waits for its best move to be sent.
+func (s *server) stop() {
+	if s.search == nil {
+		return
+	}
+
+	s.search.cancel()
+	s.search.releaseResult()
+	<-s.search.done
+	if s.search.timer != nil {
+		s.search.timer.Stop()
+	}
+	s.search = nil
+}
+
+// ponderHit switches the pondering search to the usual one: the clock of the search is started
+// and the best move is sent when the search is finished.
+func (s *server) ponderHit() {
+	if s.search == nil || s.search.released {
+		return
+	}
+
+	s.search.startClock()
+	s.search.releaseResult()
+}
+
+// info sends the result of the completed iteration of the search.
+func (s *server) info(result engine.Result) {
+	words := []string{
+		"info",
+		"depth", strconv.Itoa(result.Depth),
+		"score", score(result.Score),
+		"nodes", strconv.Itoa(result.Nodes),
+	}
+	if ms := result.Duration.Milliseconds(); ms > 0 {
+		words = append(words, "nps", strconv.FormatInt(int64(result.Nodes)*1000/ms, 10))
+	}
+	words = append(words, "time", strconv.FormatInt(result.Duration.Milliseconds(), 10), "pv")
+	for _, move := range result.PV {
+		words = append(words, move.UCI())
+	}
+
+	s.send(strings.Join(words, " "))
+}
+
+func (s *server) send(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fmt.Fprintln(s.out, line)
+}
+
+// startClock stops the search when its time is over.
+func (s *search) startClock() {
+	if s.budget > 0 {
+		s.timer = time.AfterFunc(s.budget, s.cancel)
+	}
+}
+
+func (s *search) releaseResult() {
+	if !s.released {
+		s.released = true
+		close(s.release)
+	}
+}
+
+// parseGo parses the arguments of the go command, e.g. "wtime 60000 btime 60000 winc 1000 binc 1000".
+// The time of the search is computed from the clock of the side to move.
+// The unknown arguments and the malformed values are ignored.
+func parseGo(args string, turn chess.Color) goParams {
+	var (
+		params              goParams
+		moveTime, movesToGo int
+		clocks              = [2]time.Duration{engine.NoClock, engine.NoClock}
+		increments          [2]int
+	)
+
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); i++ {
+		number := 0
+		if i+1 < len(fields) {
+			number, _ = strconv.Atoi(fields[i+1])
+		}
+
+		switch fields[i] {
+		case "infinite":
+			params.infinite = true
+		case "ponder":
+			params.ponder = true
+		case "depth":
+			params.limits.Depth = number
+		case "nodes":
+			params.limits.Nodes = number
+		case "mate":
+			params.limits.Depth = 2*number - 1
+		case "movetime":
+			moveTime = number
+		case "movestogo":
+			movesToGo = number
+		case "wtime", "btime":
+			clocks[side(fields[i])] = time.Duration(number) * time.Millisecond
+		case "winc", "binc":
+			increments[side(fields[i])] = number
+		}
+	}
+
+	own := 0
+	if turn != chess.ColorWhite {
+		own = 1
+	}
+	params.budget = budget(
+		time.Duration(moveTime)*time.Millisecond,
+		clocks[own],
+		time.Duration(increments[own])*time.Millisecond,
+		movesToGo,
+	)
+
+	return params
+}
+
+// budget returns the time of the search: the exact time of the move if it's set,
+// otherwise the time computed from the clock. It's zero if the time isn't limited, e.g. the clock isn't given.
+func budget(moveTime, remaining, increment time.Duration, movesToGo int) time.Duration {
+	if moveTime > 0 {
+		return moveTime
+	}
+
+	return engine.MoveTime(remaining, increment, movesToGo)
+}
+
+// side returns 0 for the white clock argument and 1 for the black one.
+func side(arg string) int {
+	if strings.HasPrefix(arg, "w") {
+		return 0
+	}
+
+	return 1
+}
+
+// bestMove returns the bestmove command of the result with the ponder move if it's known.
+func bestMove(result engine.Result) string {
+	command := "bestmove " + result.BestMove.UCI()
+	if len(result.PV) > 1 {
+		command += " ponder " + result.PV[1].UCI()
+	}
+
+	return command
+}
+
+// score returns the score in the UCI notation, e.g. "cp 31" or "mate -2".
+func score(s engine.Score) string {
+	if mate, ok := s.Mate(); ok {
+		return "mate " + strconv.Itoa(mate)
+	}
+
+	return "cp " + strconv.Itoa(int(s))
+}
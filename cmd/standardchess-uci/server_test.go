@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elaxer/standardchess"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTimeout is the time the GUI waits for an answer of the server.
+const testTimeout = 10 * time.Second
+
+// testGUI sends the commands to the server and reads its answers.
+type testGUI struct {
+	t     *testing.T
+	w     *io.PipeWriter
+	lines chan string
+	done  chan error
+	// infos are the info lines skipped by next.
+	infos []string
+}
+
+func TestServer_UCI(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("uci")
+	assert.Equal(t, "id name standardchess", gui.next())
+	assert.Equal(t, "id author elaxer", gui.next())
+	assert.Equal(t, "option name Hash type spin default 40 min 1 max 4096", gui.next())
+	assert.Equal(t, "option name Ponder type check default false", gui.next())
+	assert.Equal(t, "option name UCI_Chess960 type check default false", gui.next())
+	assert.Equal(t, "uciok", gui.next())
+
+	gui.send("setoption name Hash value 1")
+	gui.send("isready")
+	assert.Equal(t, "readyok", gui.next())
+
+	gui.send("quit")
+	assert.NoError(t, gui.wait())
+}
+
+func TestServer_Go(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("ucinewgame")
+	gui.send("position startpos moves e2e4 e7e5 g1f3")
+	gui.send("go depth 3")
+
+	bestMove := gui.next()
+	require.Len(t, gui.infos, 3)
+	assert.True(t, strings.HasPrefix(gui.infos[0], "info depth 1 score cp "), gui.infos[0])
+	assert.Contains(t, gui.infos[2], " pv ")
+
+	board, err := standardchess.NewBoardFromMoves([]string{"e4", "e5", "Nf3"})
+	require.NoError(t, err)
+	assertLegalBestMove(t, board, bestMove)
+}
+
+func TestServer_Go_NoTimeLeft(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("position startpos")
+	gui.send("go wtime 0 btime 1000")
+	assertLegalBestMove(t, standardchess.NewBoard(), gui.next())
+
+	gui.send("go wtime -500 btime 1000")
+	assertLegalBestMove(t, standardchess.NewBoard(), gui.next())
+}
+
+func TestServer_Go_Mate(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("position fen 6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 1")
+	gui.send("go mate 1")
+	assert.Equal(t, "bestmove a1a8", gui.next())
+	assert.Contains(t, gui.infos[len(gui.infos)-1], " score mate 1 ")
+
+	gui.send("position fen 6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 1 moves a1a8")
+	gui.send("go depth 1")
+	assert.Equal(t, "bestmove (none)", gui.next())
+}
+
+func TestServer_Go_Infinite(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("position startpos")
+	gui.send("go infinite")
+	gui.send("isready")
+	assert.Equal(t, "readyok", gui.next())
+
+	gui.send("stop")
+	assertLegalBestMove(t, standardchess.NewBoard(), gui.next())
+}
+
+func TestServer_Go_Infinite_Finished(t *testing.T) {
+	gui := newTestGUI(t)
+
+	// The search is finished at once, but the best move is sent on stop only.
+	gui.send("position startpos")
+	gui.send("go infinite depth 1")
+	time.Sleep(50 * time.Millisecond)
+	gui.send("isready")
+	assert.Equal(t, "readyok", gui.next())
+
+	gui.send("stop")
+	assertLegalBestMove(t, standardchess.NewBoard(), gui.next())
+}
+
+func TestServer_Ponder(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("setoption name Ponder value true")
+	gui.send("position startpos moves d2d4")
+	gui.send("go ponder wtime 3000 btime 3000")
+	gui.send("isready")
+	assert.Equal(t, "readyok", gui.next())
+
+	gui.send("ponderhit")
+	board, err := standardchess.NewBoardFromMoves([]string{"d4"})
+	require.NoError(t, err)
+	assertLegalBestMove(t, board, gui.next())
+}
+
+func TestServer_Chess960(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("setoption name UCI_Chess960 value true")
+	gui.send("position fen bqnbrkrn/pppppppp/8/8/8/8/PPPPPPPP/BQNBRKRN w GEge - 0 1 moves g2g3 g7g6 f1g1")
+	gui.send("go depth 1")
+	assert.True(t, strings.HasPrefix(gui.next(), "bestmove "))
+}
+
+func TestServer_Position_Error(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("position startpos moves e2e5")
+	assert.True(t, strings.HasPrefix(gui.next(), "info string illegal move: e2e5"))
+
+	gui.send("position somewhere")
+	assert.Equal(t, "info string unknown position: somewhere", gui.next())
+
+	// The previous position isn't searched.
+	gui.send("position startpos moves e2e4")
+	gui.send("position startpos moves e2e4 e7e6 e4e6")
+	assert.True(t, strings.HasPrefix(gui.next(), "info string illegal move: e4e6"))
+	gui.send("go depth 1")
+	assert.Equal(t, "bestmove (none)", gui.next())
+}
+
+func TestServer_Position_UnclaimedDraw(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("position startpos")
+	gui.send("position startpos moves g1f3 g8f6 f3g1 f6g8 g1f3 g8f6 f3g1 f6g8 e2e4")
+	gui.send("isready")
+	assert.Equal(t, "readyok", gui.next())
+	gui.send("go depth 2")
+
+	board, err := standardchess.NewBoardFromMoves([]string{"Nf3", "Nf6", "Ng1", "Ng8", "Nf3", "Nf6", "Ng1", "Ng8"})
+	require.NoError(t, err)
+	_, err = standardchess.MakeMovePastDraw(board, "e4")
+	require.NoError(t, err)
+	assertLegalBestMove(t, board, gui.next())
+}
+
+func assertLegalBestMove(t *testing.T, board standardchess.Board, line string) {
+	t.Helper()
+
+	move, ok := strings.CutPrefix(line, "bestmove ")
+	require.True(t, ok, line)
+	move, _, _ = strings.Cut(move, " ")
+
+	_, err := board.MakeMove(move)
+	assert.NoError(t, err)
+}
+
+// newTestGUI runs the server connected to the GUI by the pipes.
+func newTestGUI(t *testing.T) *testGUI {
+	t.Helper()
+
+	commandsReader, commandsWriter := io.Pipe()
+	outputReader, outputWriter := io.Pipe()
+
+	gui := &testGUI{t: t, w: commandsWriter, lines: make(chan string, 100), done: make(chan error, 1)}
+	go func() {
+		gui.done <- newServer(outputWriter).run(commandsReader)
+		_ = outputWriter.Close()
+	}()
+	go func() {
+		defer close(gui.lines)
+
+		scanner := bufio.NewScanner(outputReader)
+		for scanner.Scan() {
+			gui.lines <- scanner.Text()
+		}
+	}()
+	t.Cleanup(func() {
+		_ = commandsWriter.Close()
+		for range gui.lines {
+		}
+	})
+
+	return gui
+}
+
+func (g *testGUI) send(command string) {
+	g.t.Helper()
+
+	_, err := io.WriteString(g.w, command+"\n")
+	require.NoError(g.t, err)
+}
+
+// next returns the next answer of the server which isn't an info line about the search.
+func (g *testGUI) next() string {
+	g.t.Helper()
+
+	timeout := time.After(testTimeout)
+	for {
+		select {
+		case line, ok := <-g.lines:
+			require.True(g.t, ok, "the server has exited")
+			if strings.HasPrefix(line, "info depth") {
+				g.infos = append(g.infos, line)
+
+				continue
+			}
+
+			return line
+		case <-timeout:
+			require.FailNow(g.t, "no answer of the server")
+		}
+	}
+}
+
+// wait waits for the server to exit.
+func (g *testGUI) wait() error {
+	g.t.Helper()
+
+	select {
+	case err := <-g.done:
+		return err
+	case <-time.After(testTimeout):
+		require.FailNow(g.t, "the server hasn't exited")
+
+		return nil
+	}
+}
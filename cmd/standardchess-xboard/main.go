@@ -0,0 +1,22 @@
+// Standardchess-xboard runs the built-in engine speaking the Chess Engine Communication Protocol on stdin and stdout,
+// so it can be hosted by XBoard, WinBoard or any other GUI supporting the protocol.
+//
+// Usage:
+//
+//	standardchess-xboard
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/elaxer/standardchess/engine"
+	"github.com/elaxer/standardchess/xboard"
+)
+
+func main() {
+	if err := xboard.NewServer(engine.New(engine.DefaultHashSize)).Run(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,116 @@
+// Package eco classifies the openings of games by the Encyclopaedia of Chess Openings.
+// The table of the openings is embedded, it's taken from https://github.com/lichess-org/chess-openings.
+// The openings are matched by the positions, so the transpositions are recognized.
+package eco
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/encoding/polyglot"
+)
+
+// nameSeparator separates the name of the opening from its variation in the table.
+const nameSeparator = ": "
+
+//go:embed openings.tsv
+var openingsTSV string
+
+// loadTable parses the embedded table once.
+var loadTable = sync.OnceValue(func() *table {
+	t, err := parseTable(openingsTSV)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+})
+
+// Opening is an opening of the table.
+type Opening struct {
+	// ECO is the code of the opening, e.g. "C65".
+	ECO string
+	// Name is the full name of the opening with its variation, e.g. "Ruy Lopez: Berlin Defense".
+	Name string
+	// Moves are the moves of the opening in SAN starting from the standard initial position.
+	Moves []string
+}
+
+// table is the table of the openings by the Polyglot keys of their positions.
+type table struct {
+	openings map[uint64]Opening
+	// maxPlies is the number of the plies of the longest opening.
+	maxPlies int
+}
+
+// Classify returns the opening of the game played on the board:
+// the opening of the last position of the game which is found in the table.
+// The second value is false if no position of the game is in the table.
+func Classify(board chess.Board) (Opening, bool) {
+	t := loadTable()
+
+	moveHistory := board.MoveHistory()
+	if len(moveHistory) > t.maxPlies {
+		moveHistory = moveHistory[:t.maxPlies]
+	}
+
+	initial, err := fen.EncodeInitial(board)
+	if err != nil {
+		return Opening{}, false
+	}
+	replay, err := fen.Decode(initial.String())
+	if err != nil {
+		return Opening{}, false
+	}
+
+	opening, found := t.lookup(replay)
+	for _, move := range moveHistory {
+		if _, err := standardchess.MakeMovePastDraw(replay, move.String()); err != nil {
+			break
+		}
+		if o, ok := t.lookup(replay); ok {
+			opening, found = o, true
+		}
+	}
+
+	return opening, found
+}
+
+// Lookup returns the opening of the current position on the board.
+// The second value is false if the position isn't in the table.
+func Lookup(board chess.Board) (Opening, bool) {
+	return loadTable().lookup(board)
+}
+
+// Family returns the name of the opening without its variation, e.g. "Ruy Lopez".
+func (o Opening) Family() string {
+	family, _, _ := strings.Cut(o.Name, nameSeparator)
+
+	return family
+}
+
+// Variation returns the variation of the opening, e.g. "Berlin Defense",
+// an empty string if the opening has no variation.
+func (o Opening) Variation() string {
+	_, variation, _ := strings.Cut(o.Name, nameSeparator)
+
+	return variation
+}
+
+func (o Opening) String() string {
+	return o.ECO + " " + o.Name
+}
+
+func (t *table) lookup(board chess.Board) (Opening, bool) {
+	if board.Squares().EdgePosition() != standardchess.EdgePosition {
+		return Opening{}, false
+	}
+
+	opening, ok := t.openings[polyglot.Key(board)]
+
+	return opening, ok
+}
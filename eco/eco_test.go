@@ -0,0 +1,103 @@
+package eco_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/eco"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name      string
+		moves     []string
+		wantECO   string
+		wantName  string
+		wantFound bool
+	}{
+		{"initial", nil, "", "", false},
+		{"king_pawn", []string{"e4"}, "B00", "King's Pawn", true},
+		{"sicilian", []string{"e4", "c5"}, "B20", "Sicilian Defense", true},
+		{"berlin", []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "Nf6"}, "C65", "Ruy Lopez: Berlin Defense", true},
+		{
+			"out_of_book",
+			[]string{"e4", "e5", "Nf3", "Nc6", "Bb5", "Nf6", "a3", "a6", "Ra2"},
+			"C65",
+			"Ruy Lopez: Berlin Defense",
+			true,
+		},
+		{"transposition", []string{"Nf3", "d5", "d4"}, "D02", "Queen's Pawn Game: Zukertort Variation", true},
+		{"nimzo_indian", []string{"c4", "Nf6", "Nc3", "e6", "d4", "Bb4"}, "E20", "Nimzo-Indian Defense", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := standardchess.NewBoardFromMoves(tt.moves)
+			require.NoError(t, err)
+
+			opening, ok := eco.Classify(board)
+			require.Equal(t, tt.wantFound, ok)
+			assert.Equal(t, tt.wantECO, opening.ECO)
+			assert.Equal(t, tt.wantName, opening.Name)
+			assert.Len(t, board.MoveHistory(), len(tt.moves))
+		})
+	}
+}
+
+func TestClassify_FEN(t *testing.T) {
+	board, err := fen.Decode("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1")
+	require.NoError(t, err)
+
+	opening, ok := eco.Classify(board)
+	require.True(t, ok)
+	assert.Equal(t, "B00", opening.ECO)
+
+	_, err = board.MakeMove("c5")
+	require.NoError(t, err)
+
+	opening, ok = eco.Classify(board)
+	require.True(t, ok)
+	assert.Equal(t, "B20", opening.ECO)
+}
+
+func TestLookup(t *testing.T) {
+	board, err := standardchess.NewBoardFromMoves([]string{"e4", "e5", "Nf3", "Nc6", "Bb5", "Nf6", "a3"})
+	require.NoError(t, err)
+
+	_, ok := eco.Lookup(board)
+	assert.False(t, ok)
+
+	_, err = board.UndoLastMove()
+	require.NoError(t, err)
+
+	opening, ok := eco.Lookup(board)
+	require.True(t, ok)
+	assert.Equal(t, "C65", opening.ECO)
+	assert.Equal(t, []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "Nf6"}, opening.Moves)
+}
+
+func TestOpening_Family(t *testing.T) {
+	tests := []struct {
+		name          string
+		opening       eco.Opening
+		wantFamily    string
+		wantVariation string
+	}{
+		{"variation", eco.Opening{Name: "Ruy Lopez: Berlin Defense"}, "Ruy Lopez", "Berlin Defense"},
+		{
+			"subvariation",
+			eco.Opening{Name: "Sicilian Defense: Najdorf Variation, English Attack"},
+			"Sicilian Defense",
+			"Najdorf Variation, English Attack",
+		},
+		{"no_variation", eco.Opening{Name: "Sicilian Defense"}, "Sicilian Defense", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantFamily, tt.opening.Family())
+			assert.Equal(t, tt.wantVariation, tt.opening.Variation())
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package eco
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/encoding/polyglot"
+)
+
+// tableColumnsCount is the number of the columns of the table: eco, name, pgn and epd.
+const tableColumnsCount = 4
+
+var errTable = errors.New("invalid eco table")
+
+// parseTable parses the tab separated table of the openings with the header row.
+// The EPD of an opening is its position without the halfmove clock and the move number.
+func parseTable(tsv string) (*table, error) {
+	t := &table{openings: make(map[uint64]Opening)}
+
+	scanner := bufio.NewScanner(strings.NewReader(tsv))
+	scanner.Scan()
+	for line := 2; scanner.Scan(); line++ {
+		columns := strings.Split(scanner.Text(), "\t")
+		if len(columns) != tableColumnsCount {
+			return nil, fmt.Errorf("%w: line %d: %d columns instead of %d", errTable, line, len(columns), tableColumnsCount)
+		}
+
+		board, err := fen.Decode(columns[3] + " 0 1")
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", errTable, line, err)
+		}
+
+		opening := Opening{ECO: columns[0], Name: columns[1], Moves: parseMoves(columns[2])}
+		if key := polyglot.Key(board); !t.has(key) {
+			t.openings[key] = opening
+		}
+		t.maxPlies = max(t.maxPlies, len(opening.Moves))
+	}
+
+	return t, scanner.Err()
+}
+
+func (t *table) has(key uint64) bool {
+	_, ok := t.openings[key]
+
+	return ok
+}
+
+// parseMoves returns the moves of the movetext without the move numbers, e.g. "1. e4 e5 2. Nf3".
+func parseMoves(movetext string) []string {
+	tokens := strings.Fields(movetext)
+	moves := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if !strings.HasSuffix(token, ".") {
+			moves = append(moves, token)
+		}
+	}
+
+	return moves
+}
@@ -0,0 +1,349 @@
+// Package epd contains functions for encoding positions into the Extended Position Description (EPD) format
+// and vice versa. EPD is used by test suites like WAC and STS: it's the first four fields of FEN
+// followed by the operations, e.g. `2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001";`.
+package epd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+)
+
+// The opcodes of the common operations.
+const (
+	// OpcodeBestMove is the opcode of the best moves in SAN.
+	OpcodeBestMove = "bm"
+	// OpcodeAvoidMove is the opcode of the moves to avoid in SAN.
+	OpcodeAvoidMove = "am"
+	// OpcodeID is the opcode of the position identifier.
+	OpcodeID = "id"
+	// OpcodeAnalysisCountDepth is the opcode of the depth of the analysis in plies.
+	OpcodeAnalysisCountDepth = "acd"
+	// OpcodeHalfmoveClock is the opcode of the halfmove clock of the position.
+	OpcodeHalfmoveClock = "hmvc"
+	// OpcodeFullmoveNumber is the opcode of the move number of the position.
+	OpcodeFullmoveNumber = "fmvn"
+)
+
+// positionFieldsCount is the number of the FEN fields of an EPD record.
+const positionFieldsCount = 4
+
+var ErrDecoding = errors.New("error decoding EPD string")
+
+var (
+	operandEscaper   = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	operandUnescaper = strings.NewReplacer(`\"`, `"`, `\\`, `\`)
+)
+
+// EPD is a position in the Extended Position Description format with its operations.
+type EPD struct {
+	// position is the first four fields of FEN: placement, turn, castling rights and en passant square.
+	position   string
+	operations []Operation
+}
+
+// Operation is an operation of an EPD record: the opcode with its operands.
+type Operation struct {
+	Opcode   string
+	Operands []string
+}
+
+// FromString parses the EPD record.
+// The operations are separated by semicolons, the operands containing spaces or semicolons are quoted.
+func FromString(str string) (EPD, error) {
+	fields := strings.Fields(str)
+	if len(fields) < positionFieldsCount {
+		return EPD{}, fmt.Errorf("%w: the position must have %d fields", ErrDecoding, positionFieldsCount)
+	}
+
+	position := strings.Join(fields[:positionFieldsCount], " ")
+	if _, err := fen.FromString(position + " 0 1"); err != nil {
+		return EPD{}, fmt.Errorf("%w: invalid position %q", ErrDecoding, position)
+	}
+
+	operations, err := parseOperations(strings.TrimSpace(trimFields(str, positionFieldsCount)))
+	if err != nil {
+		return EPD{}, err
+	}
+
+	return EPD{position, operations}, nil
+}
+
+// Encode creates the EPD record of the position on the board with the operations.
+func Encode(board chess.Board, operations ...Operation) EPD {
+	fields := strings.Fields(fen.Encode(board).String())
+
+	return EPD{strings.Join(fields[:positionFieldsCount], " "), slices.Clone(operations)}
+}
+
+// Position returns the first four fields of FEN of the record.
+func (e EPD) Position() string {
+	return e.position
+}
+
+// FEN returns the FEN of the position.
+// The halfmove clock and the move number are taken from the hmvc and fmvn operations, 0 and 1 by default.
+func (e EPD) FEN() string {
+	halfmoveClock, ok := e.intOperand(OpcodeHalfmoveClock)
+	if !ok {
+		halfmoveClock = 0
+	}
+	moveNumber, ok := e.intOperand(OpcodeFullmoveNumber)
+	if !ok {
+		moveNumber = 1
+	}
+
+	return fmt.Sprintf("%s %d %d", e.position, halfmoveClock, moveNumber)
+}
+
+// Board creates the board with the position of the record, see FEN.
+func (e EPD) Board() (standardchess.Board, error) {
+	return fen.Decode(e.FEN())
+}
+
+// Operations returns the operations of the record in their order.
+func (e EPD) Operations() []Operation {
+	return e.operations
+}
+
+// Operands returns the operands of the operation with the opcode.
+// The second value is false if there is no such operation.
+func (e EPD) Operands(opcode string) ([]string, bool) {
+	for _, operation := range e.operations {
+		if operation.Opcode == opcode {
+			return operation.Operands, true
+		}
+	}
+
+	return nil, false
+}
+
+// Set sets the operands of the operation with the opcode, the operation is appended if there is no such operation.
+func (e *EPD) Set(opcode string, operands ...string) {
+	i := slices.IndexFunc(e.operations, func(operation Operation) bool { return operation.Opcode == opcode })
+	if i < 0 {
+		e.operations = append(e.operations, Operation{opcode, operands})
+
+		return
+	}
+
+	e.operations = slices.Clone(e.operations)
+	e.operations[i].Operands = operands
+}
+
+// Delete deletes the operation with the opcode.
+func (e *EPD) Delete(opcode string) {
+	e.operations = slices.DeleteFunc(slices.Clone(e.operations), func(operation Operation) bool {
+		return operation.Opcode == opcode
+	})
+}
+
+// ID returns the identifier of the position given by the id operation.
+func (e EPD) ID() string {
+	return e.stringOperand(OpcodeID)
+}
+
+// Comment returns the comment given by the c0-c9 operation with the number.
+func (e EPD) Comment(n int) string {
+	return e.stringOperand("c" + strconv.Itoa(n))
+}
+
+// AnalysisDepth returns the depth of the analysis given by the acd operation.
+// The second value is false if there is no such operation.
+func (e EPD) AnalysisDepth() (int, bool) {
+	return e.intOperand(OpcodeAnalysisCountDepth)
+}
+
+// BestMoves returns the legal moves given by the bm operation.
+// ErrDecoding is returned if a move isn't legal in the position.
+func (e EPD) BestMoves() ([]standardchess.Move, error) {
+	return e.Moves(OpcodeBestMove)
+}
+
+// AvoidMoves returns the legal moves given by the am operation.
+// ErrDecoding is returned if a move isn't legal in the position.
+func (e EPD) AvoidMoves() ([]standardchess.Move, error) {
+	return e.Moves(OpcodeAvoidMove)
+}
+
+// Moves resolves the operands of the operation with the opcode as the moves in SAN against the position.
+// The check and checkmate suffixes and annotations like "!" or "?" of the operands don't matter,
+// the moves in UCI notation are accepted too.
+// The moves are nil if there is no such operation.
+// ErrDecoding is returned if a move isn't legal in the position.
+func (e EPD) Moves(opcode string) ([]standardchess.Move, error) {
+	operands, ok := e.Operands(opcode)
+	if !ok {
+		return nil, nil
+	}
+
+	board, err := e.Board()
+	if err != nil {
+		return nil, err
+	}
+
+	legalMoves := standardchess.MoveList(board)
+	moves := make([]standardchess.Move, 0, len(operands))
+	for _, operand := range operands {
+		i := slices.IndexFunc(legalMoves, func(move standardchess.Move) bool {
+			return normalizeSAN(move.SAN()) == normalizeSAN(operand) || move.UCI() == operand
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("%w: the move %q of the %s operation isn't legal", ErrDecoding, operand, opcode)
+		}
+
+		moves = append(moves, legalMoves[i])
+	}
+
+	return moves, nil
+}
+
+// String returns the EPD record with each operation terminated by a semicolon.
+func (e EPD) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.position)
+	for _, operation := range e.operations {
+		sb.WriteString(" " + operation.String())
+	}
+
+	return sb.String()
+}
+
+// String returns the operation terminated by a semicolon.
+// The operands of the id and c0-c9 operations and the operands containing spaces or semicolons are quoted.
+func (o Operation) String() string {
+	var sb strings.Builder
+	sb.WriteString(o.Opcode)
+	for _, operand := range o.Operands {
+		sb.WriteByte(' ')
+		if isStringOpcode(o.Opcode) || operand == "" || strings.ContainsAny(operand, " \t;\"") {
+			sb.WriteString(`"` + operandEscaper.Replace(operand) + `"`)
+		} else {
+			sb.WriteString(operand)
+		}
+	}
+	sb.WriteByte(';')
+
+	return sb.String()
+}
+
+func (e EPD) stringOperand(opcode string) string {
+	operands, _ := e.Operands(opcode)
+	if len(operands) == 0 {
+		return ""
+	}
+
+	return operands[0]
+}
+
+func (e EPD) intOperand(opcode string) (int, bool) {
+	operand, err := strconv.Atoi(e.stringOperand(opcode))
+	if err != nil {
+		return 0, false
+	}
+
+	return operand, true
+}
+
+// parseOperations parses the operations separated by semicolons.
+// The last operation may be not terminated by a semicolon.
+func parseOperations(str string) ([]Operation, error) {
+	operations := make([]Operation, 0)
+
+	var operation *Operation
+	for len(str) > 0 {
+		var token string
+		var isQuoted bool
+		switch str[0] {
+		case ' ', '\t', '\r', '\n':
+			str = str[1:]
+
+			continue
+		case ';':
+			if operation == nil {
+				return nil, fmt.Errorf("%w: operation without opcode", ErrDecoding)
+			}
+			operations = append(operations, *operation)
+			operation = nil
+			str = str[1:]
+
+			continue
+		case '"':
+			end := quotedLength(str)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated string %s", ErrDecoding, str)
+			}
+			token, str, isQuoted = operandUnescaper.Replace(str[1:end-1]), str[end:], true
+		default:
+			end := strings.IndexAny(str, " \t\r\n;")
+			if end < 0 {
+				end = len(str)
+			}
+			token, str = str[:end], str[end:]
+		}
+
+		if operation == nil {
+			if isQuoted {
+				return nil, fmt.Errorf("%w: invalid opcode %q", ErrDecoding, token)
+			}
+			operation = &Operation{Opcode: token}
+		} else {
+			operation.Operands = append(operation.Operands, token)
+		}
+	}
+
+	if operation != nil {
+		operations = append(operations, *operation)
+	}
+
+	return operations, nil
+}
+
+// quotedLength returns the length of the quoted string at the start of str including the quotes,
+// -1 if it isn't terminated. The quotes in the string are escaped by the backslash.
+func quotedLength(str string) int {
+	for i := 1; i < len(str); i++ {
+		switch str[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// trimFields returns the string without the first n fields separated by white spaces.
+func trimFields(str string, n int) string {
+	for range n {
+		str = strings.TrimLeft(str, " \t")
+		if i := strings.IndexAny(str, " \t"); i >= 0 {
+			str = str[i:]
+		} else {
+			str = ""
+		}
+	}
+
+	return str
+}
+
+func normalizeSAN(san string) string {
+	san = strings.TrimRight(san, "+#!?")
+
+	return strings.ReplaceAll(san, "0", "O")
+}
+
+func isStringOpcode(opcode string) bool {
+	if opcode == OpcodeID {
+		return true
+	}
+
+	return len(opcode) == 2 && opcode[0] == 'c' && opcode[1] >= '0' && opcode[1] <= '9'
+}
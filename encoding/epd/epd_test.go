@@ -0,0 +1,154 @@
+package epd_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/epd"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const wac001 = `2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001";`
+
+func TestFromString(t *testing.T) {
+	e, err := epd.FromString(wac001)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - -", e.Position())
+	assert.Equal(t, "WAC.001", e.ID())
+	assert.Equal(t, []epd.Operation{
+		{epd.OpcodeBestMove, []string{"Qg6"}},
+		{epd.OpcodeID, []string{"WAC.001"}},
+	}, e.Operations())
+	assert.Equal(t, wac001, e.String())
+
+	moves, err := e.BestMoves()
+	require.NoError(t, err)
+	require.Len(t, moves, 1)
+	assert.Equal(t, "Qg6", moves[0].SAN())
+	assert.Equal(t, "g3g6", moves[0].UCI())
+}
+
+func TestFromString_Operations(t *testing.T) {
+	e, err := epd.FromString(`r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq -  ` +
+		`bm Bb5 Bc4!; am Qe2 Ba6?; c0 "Ruy Lopez; or Italian"; c1 "The \"best\" move"; acd 12; hmvc 2; fmvn 3; noop`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Ruy Lopez; or Italian", e.Comment(0))
+	assert.Equal(t, `The "best" move`, e.Comment(1))
+	assert.Empty(t, e.Comment(2))
+
+	depth, ok := e.AnalysisDepth()
+	assert.True(t, ok)
+	assert.Equal(t, 12, depth)
+
+	operands, ok := e.Operands("noop")
+	assert.True(t, ok)
+	assert.Empty(t, operands)
+
+	assert.Equal(t, "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3", e.FEN())
+
+	bestMoves, err := e.BestMoves()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bb5", "Bc4"}, sans(bestMoves))
+
+	avoidMoves, err := e.AvoidMoves()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Qe2", "Ba6"}, sans(avoidMoves))
+
+	assert.Equal(t, `r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - `+
+		`bm Bb5 Bc4!; am Qe2 Ba6?; c0 "Ruy Lopez; or Italian"; c1 "The \"best\" move"; acd 12; hmvc 2; fmvn 3; noop;`,
+		e.String())
+
+	parsed, err := epd.FromString(e.String())
+	require.NoError(t, err)
+	assert.Equal(t, e, parsed)
+}
+
+func TestFromString_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		str  string
+	}{
+		{"empty", ""},
+		{"no_castlings", "4k3/8/8/8/8/8/8/4K3 w"},
+		{"invalid_position", "4k3/8/8/8/8/8/8/4K3 x - - bm Kd1;"},
+		{"unterminated_string", `4k3/8/8/8/8/8/8/4K3 w - - id "WAC;`},
+		{"quoted_opcode", `4k3/8/8/8/8/8/8/4K3 w - - "id" "WAC";`},
+		{"no_opcode", `4k3/8/8/8/8/8/8/4K3 w - - ;`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := epd.FromString(tt.str)
+			assert.ErrorIs(t, err, epd.ErrDecoding)
+		})
+	}
+}
+
+func TestEPD_Moves(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		want    []string
+		wantErr bool
+	}{
+		{"check_suffix", "4k3/8/8/8/8/8/8/R3K3 w Q - bm Ra8;", []string{"Ra8+"}, false},
+		{"castling_zeros", "4k3/8/8/8/8/8/8/R3K3 w Q - bm 0-0-0;", []string{"O-O-O"}, false},
+		{"uci", "4k3/8/8/8/8/8/8/R3K3 w Q - bm a1a8;", []string{"Ra8+"}, false},
+		{"no_operation", "4k3/8/8/8/8/8/8/R3K3 w Q - id \"x\";", nil, false},
+		{"illegal", "4k3/8/8/8/8/8/8/R3K3 w Q - bm Rb8;", nil, true},
+		{"other_side", "4k3/8/8/8/8/8/8/R3K3 w Q - bm Kd8;", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := epd.FromString(tt.str)
+			require.NoError(t, err)
+
+			moves, err := e.BestMoves()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, epd.ErrDecoding)
+
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Nil(t, moves)
+			} else {
+				assert.Equal(t, tt.want, sans(moves))
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	board, err := standardchess.NewBoardFromMoves([]string{"e4", "e5", "Nf3"})
+	require.NoError(t, err)
+
+	e := epd.Encode(board, epd.Operation{epd.OpcodeID, []string{"Opening"}})
+	e.Set(epd.OpcodeBestMove, "Nc6")
+	e.Set(epd.OpcodeID, "King's Knight")
+	e.Set("c0", "")
+
+	assert.Equal(t, `rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - id "King's Knight"; bm Nc6; c0 "";`,
+		e.String())
+
+	e.Delete("c0")
+	e.Set(epd.OpcodeHalfmoveClock, "1")
+	e.Set(epd.OpcodeFullmoveNumber, "2")
+
+	decoded, err := e.Board()
+	require.NoError(t, err)
+	assert.Equal(t, fen.Encode(board).String(), fen.Encode(decoded).String())
+}
+
+func sans(moves []standardchess.Move) []string {
+	sans := make([]string, 0, len(moves))
+	for _, move := range moves {
+		sans = append(sans, move.SAN())
+	}
+
+	return sans
+}
@@ -0,0 +1,42 @@
+package epd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Parse reads the EPD records from the reader, one record per line.
+// The empty lines and the lines starting with "#" are skipped.
+// The errors of the records are wrapped with their line numbers, the parsing is continued then.
+// The iteration is stopped after the error of the reader.
+func Parse(r io.Reader) iter.Seq2[EPD, error] {
+	return func(yield func(EPD, error) bool) {
+		br := bufio.NewReader(r)
+		for lineNumber := 1; ; lineNumber++ {
+			line, err := br.ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				yield(EPD{}, err)
+
+				return
+			}
+
+			if str := strings.TrimSpace(line); str != "" && !strings.HasPrefix(str, "#") {
+				epd, parseErr := FromString(str)
+				if parseErr != nil {
+					parseErr = fmt.Errorf("line %d: %w", lineNumber, parseErr)
+				}
+				if !yield(epd, parseErr) {
+					return
+				}
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package epd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/elaxer/standardchess/encoding/epd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	data := "# Win at Chess\n" +
+		wac001 + "\r\n" +
+		"\n" +
+		"4k3/8/8/8/8/8/8/4K3 x - - id \"broken\";\n" +
+		`r1b1k2r/ppppnppp/2n2q2/2b5/3NP3/2P1B3/PP3PPP/RN1QKB1R w KQkq - bm Nxc6; id "WAC.004";`
+
+	var ids []string
+	var errs []error
+	for e, err := range epd.Parse(strings.NewReader(data)) {
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+		ids = append(ids, e.ID())
+	}
+
+	assert.Equal(t, []string{"WAC.001", "WAC.004"}, ids)
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], epd.ErrDecoding)
+	assert.Contains(t, errs[0].Error(), "line 4")
+}
@@ -12,7 +12,9 @@ import (
 	"github.com/elaxer/chess"
 	"github.com/elaxer/rgx"
 	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/internal/move/castling"
 	"github.com/elaxer/standardchess/internal/piece"
+	"github.com/elaxer/standardchess/internal/setup"
 )
 
 // ErrDecoding is returned when there is an error decoding a FEN string.
@@ -30,7 +32,26 @@ var regexpFENDecode = regexp.MustCompile(
 // Decode decodes a FEN string into a chess board.
 // The FEN string should match the regular expression defined in Regexp.
 // It returns an error if the FEN string is invalid or if there is an error creating the board or pieces.
-func Decode(fen string) (chess.Board, error) {
+//
+// If the string is a full FEN, the decoded board takes over its castling rights,
+// en passant target square, halfmove clock and move number.
+// Otherwise, only the placement and the turn are decoded
+// and the castling rights are given to every king and rook standing on their back rank.
+//
+// The castling rights may be written as in Shredder-FEN or X-FEN.
+// The board is considered a Chess960 one if the castling rights are written by files
+// or the king or a castling rook of the standard board isn't on its standard square.
+func Decode(fen string) (standardchess.Board, error) {
+	return decode(fen, false)
+}
+
+// DecodeChess960 decodes a FEN string into a Chess960 board in the same way as Decode does.
+// Unlike Decode, it doesn't need to guess the variant, e.g. for the standard initial position.
+func DecodeChess960(fen string) (standardchess.Board, error) {
+	return decode(fen, true)
+}
+
+func decode(fen string, isChess960 bool) (standardchess.Board, error) {
 	data, err := rgx.Group(regexpFENDecode, fen)
 	if err != nil {
 		return nil, err
@@ -57,12 +78,78 @@ func Decode(fen string) (chess.Board, error) {
 		maps.Copy(placement, rowPlacement)
 	}
 
-	return standardchess.NewBoardEmpty(
+	boardSetup := setup.Default()
+	full, fullErr := FromString(fen)
+	if fullErr == nil {
+		boardSetup = setup.Setup{
+			EnPassantSquare: full.EnPassantSquare(),
+			HalfmoveClock:   full.HalfmoveClock(),
+			MoveNumber:      full.MoveNumber(),
+			Chess960:        full.isChess960(),
+		}
+	}
+	boardSetup.Chess960 = boardSetup.Chess960 || isChess960
+
+	board, err := standardchess.NewBoardWithSetup(
 		color(data["turn"]),
 		placement,
 		//nolint:gosec
 		chess.NewPosition(maxFile-1, chess.Rank(len(rows))),
+		boardSetup,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	markMovedPieces(board.Squares())
+	if fullErr == nil {
+		revokeCastlings(board, full)
+	}
+
+	return board, nil
+}
+
+// markMovedPieces marks pawns outside their initial rank,
+// kings and rooks outside their back rank as moved,
+// so that they can't make a two-square move or castle.
+func markMovedPieces(squares *chess.Squares) {
+	edgeRank := squares.EdgePosition().Rank
+	for position, p := range squares.Iter() {
+		if p == nil {
+			continue
+		}
+
+		backRank, pawnRank := chess.RankMin, chess.RankMin+1
+		if p.Color().IsBlack() {
+			backRank, pawnRank = edgeRank, edgeRank-1
+		}
+
+		switch p.Notation() {
+		case piece.NotationPawn:
+			p.SetIsMoved(position.Rank != pawnRank)
+		case piece.NotationKing, piece.NotationRook:
+			p.SetIsMoved(position.Rank != backRank)
+		}
+	}
+}
+
+// revokeCastlings marks the kings and rooks as moved according to the castling rights of the FEN.
+// If a castling right is written by the file, the rook on this file becomes the castling one.
+func revokeCastlings(board chess.Board, f FEN) {
+	for _, side := range [...]chess.Color{chess.ColorWhite, chess.ColorBlack} {
+		for _, castlingType := range [...]castling.CastlingType{castling.TypeShort, castling.TypeLong} {
+			if !f.castlings[side][castlingType] {
+				castling.RevokeRight(castlingType, side, board)
+
+				continue
+			}
+
+			if file := f.castlingFiles[side][castlingType]; file != 0 {
+				// A right without a rook is ignored in the same way as the standard rights are.
+				_ = castling.GrantRight(castlingType, side, board, file)
+			}
+		}
+	}
 }
 
 func placementFromRow(
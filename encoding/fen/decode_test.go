@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
 	"github.com/elaxer/standardchess/encoding/fen"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -150,3 +151,121 @@ func TestDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecode_RoundTrip(t *testing.T) {
+	tests := []string{
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		"rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6 0 2",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"r3k2r/8/8/8/8/8/8/R3K2R b Kq - 17 42",
+		"r3k2r/8/8/8/8/8/8/R3K2R w - - 3 60",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+		"4k3/8/8/8/8/8/8/4K2R w K - 99 120",
+	}
+	for _, fenStr := range tests {
+		t.Run(fenStr, func(t *testing.T) {
+			board, err := fen.Decode(fenStr)
+			require.NoError(t, err)
+			assert.Equal(t, fenStr, fen.Encode(board).String())
+		})
+	}
+}
+
+func TestDecode_Castlings(t *testing.T) {
+	board, err := fen.Decode("r3k2r/8/8/8/8/8/8/R3K2R w Kq - 0 1")
+	require.NoError(t, err)
+
+	_, err = board.MakeMove("O-O-O")
+	require.Error(t, err)
+	_, err = board.MakeMove("O-O")
+	require.NoError(t, err)
+
+	_, err = board.MakeMove("O-O")
+	require.Error(t, err)
+	_, err = board.MakeMove("O-O-O")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2kr3r/8/8/8/8/8/8/R4RK1 w - - 2 2", fen.Encode(board).String())
+}
+
+func TestDecode_Chess960(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		want bool
+	}{
+		{"standard", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", false},
+		{"no_castlings", "1r2k1r1/8/8/8/8/8/8/1R2K1R1 w - - 0 1", false},
+		{"placement", "bbqnnrkr/pppppppp/8/8/8/8/PPPPPPPP/BBQNNRKR", false},
+		{"x_fen", "bbqnnrkr/pppppppp/8/8/8/8/PPPPPPPP/BBQNNRKR w KQkq - 0 1", true},
+		{"shredder", "r3k2r/8/8/8/8/8/8/R3K2R w HAha - 0 1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := fen.Decode(tt.fen)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, board.Setup().Chess960)
+		})
+	}
+
+	board, err := fen.DecodeChess960("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	require.NoError(t, err)
+	assert.True(t, board.Setup().Chess960)
+}
+
+func TestDecode_Chess960Castlings(t *testing.T) {
+	board, err := fen.Decode("1r1kr2r/pppppppp/8/8/8/8/PPPPPPPP/1R1KR2R w Eb - 0 1")
+	require.NoError(t, err)
+
+	_, err = board.MakeMove("O-O-O")
+	require.Error(t, err)
+	_, err = board.MakeMove("O-O")
+	require.NoError(t, err)
+	assert.Equal(t, "1r1kr2r/pppppppp/8/8/8/8/PPPPPPPP/1R3RKR b b - 1 1", fen.Encode(board).ShredderString())
+
+	_, err = board.MakeMove("O-O")
+	require.Error(t, err)
+	_, err = board.MakeMove("O-O-O")
+	require.NoError(t, err)
+	assert.Equal(t, "2krr2r/pppppppp/8/8/8/8/PPPPPPPP/1R3RKR w - - 2 2", fen.Encode(board).String())
+}
+
+func TestDecode_EnPassant(t *testing.T) {
+	board, err := fen.Decode("rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3")
+	require.NoError(t, err)
+
+	_, err = board.MakeMove("exd6")
+	require.Error(t, err)
+	_, err = board.MakeMove("exf6")
+	require.NoError(t, err)
+	assert.Equal(t, "rnbqkbnr/ppp1p1pp/5P2/3p4/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3", fen.Encode(board).String())
+
+	_, err = board.UndoLastMove()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3",
+		fen.Encode(board).String(),
+	)
+}
+
+func TestDecode_Pawns(t *testing.T) {
+	board, err := fen.Decode("4k3/8/8/8/8/4P3/8/4K3 w - - 0 1")
+	require.NoError(t, err)
+
+	_, err = board.MakeMove("e5")
+	assert.Error(t, err)
+}
+
+func TestDecode_Clocks(t *testing.T) {
+	board, err := fen.Decode("4k3/8/8/8/8/8/8/4K2R b K - 99 120")
+	require.NoError(t, err)
+
+	_, err = board.MakeMove("Kd7")
+	require.NoError(t, err)
+
+	assert.Equal(t, "8/3k4/8/8/8/8/8/4K2R w K - 100 121", fen.Encode(board).String())
+	assert.Equal(t, standardchess.StateFiftyMoves, board.State())
+}
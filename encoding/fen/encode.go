@@ -2,12 +2,17 @@ package fen
 
 import (
 	"iter"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
 	"github.com/elaxer/standardchess/internal/move/castling"
 	"github.com/elaxer/standardchess/internal/move/enpassant"
+	"github.com/elaxer/standardchess/internal/mover"
+	"github.com/elaxer/standardchess/internal/piece"
+	"github.com/elaxer/standardchess/internal/setup"
 	"github.com/elaxer/standardchess/metric"
 )
 
@@ -27,12 +32,86 @@ func Encode(board chess.Board) FEN {
 		placement:       encodeSquares(board.Squares()),
 		turn:            board.Turn(),
 		castlings:       castlings(board),
+		castlingFiles:   castlingFiles(board),
 		enPassantSquare: enpassant.EnPassantTargetSquare(board),
 		halfmoveClock:   metric.HalfmoveClock(board).Value().(int),
-		moveNumber:      len(board.MoveHistory())/2 + 1,
+		moveNumber:      moveNumber(board),
 	}
 }
 
+// EncodeInitial encodes the position the game on the board was started from.
+// The moves of the history are undone on a copy of the board, the board itself isn't changed.
+func EncodeInitial(board chess.Board) (FEN, error) {
+	if board == nil {
+		return FEN{}, nil
+	}
+
+	moveHistory := board.MoveHistory()
+
+	turn := board.Turn()
+	if len(moveHistory)%2 == 1 {
+		turn = !turn
+	}
+
+	placement := make(map[chess.Position]chess.Piece, 32)
+	for position, p := range board.Squares().Iter() {
+		if p == nil {
+			continue
+		}
+
+		clone, err := piece.New(p.Notation(), p.Color())
+		if err != nil {
+			return FEN{}, err
+		}
+		clone.SetIsMoved(p.IsMoved())
+		placement[position] = clone
+	}
+
+	initial, err := standardchess.NewBoardWithSetup(
+		turn,
+		placement,
+		board.Squares().EdgePosition(),
+		setup.FromBoard(board),
+	)
+	if err != nil {
+		return FEN{}, err
+	}
+
+	// The captured pieces are placed back on the copy and may be changed by undoing their moves.
+	capturedPieces := board.CapturedPieces()
+	wereMoved := make([]bool, len(capturedPieces))
+	for i, p := range capturedPieces {
+		wereMoved[i] = p.IsMoved()
+	}
+	defer func() {
+		for i, p := range capturedPieces {
+			p.SetIsMoved(wereMoved[i])
+		}
+	}()
+
+	for _, move := range slices.Backward(moveHistory) {
+		if err := mover.UndoMove(move, initial); err != nil {
+			return FEN{}, err
+		}
+	}
+
+	return Encode(initial), nil
+}
+
+func moveNumber(board chess.Board) int {
+	plies := len(board.MoveHistory())
+
+	initialTurn := board.Turn()
+	if plies%2 == 1 {
+		initialTurn = !initialTurn
+	}
+	if initialTurn.IsBlack() {
+		plies++
+	}
+
+	return setup.FromBoard(board).MoveNumber + plies/2
+}
+
 func encodeSquares(squares *chess.Squares) string {
 	var fenSb strings.Builder
 	for _, row := range squares.IterOverRows(true) {
@@ -72,12 +151,26 @@ func encodeRow(row iter.Seq2[chess.File, chess.Piece]) string {
 func castlings(board chess.Board) map[chess.Color]map[castling.CastlingType]bool {
 	return map[chess.Color]map[castling.CastlingType]bool{
 		chess.ColorWhite: {
-			castling.TypeShort: castling.ValidateMoveWithObstacle(castling.TypeShort, chess.ColorWhite, board) == nil,
-			castling.TypeLong:  castling.ValidateMoveWithObstacle(castling.TypeLong, chess.ColorWhite, board) == nil,
+			castling.TypeShort: castling.ValidateRight(castling.TypeShort, chess.ColorWhite, board) == nil,
+			castling.TypeLong:  castling.ValidateRight(castling.TypeLong, chess.ColorWhite, board) == nil,
 		},
 		chess.ColorBlack: {
-			castling.TypeShort: castling.ValidateMoveWithObstacle(castling.TypeShort, chess.ColorBlack, board) == nil,
-			castling.TypeLong:  castling.ValidateMoveWithObstacle(castling.TypeLong, chess.ColorBlack, board) == nil,
+			castling.TypeShort: castling.ValidateRight(castling.TypeShort, chess.ColorBlack, board) == nil,
+			castling.TypeLong:  castling.ValidateRight(castling.TypeLong, chess.ColorBlack, board) == nil,
 		},
 	}
 }
+
+func castlingFiles(board chess.Board) map[chess.Color]map[castling.CastlingType]chess.File {
+	files := make(map[chess.Color]map[castling.CastlingType]chess.File, 2)
+	for _, side := range [...]chess.Color{chess.ColorWhite, chess.ColorBlack} {
+		files[side] = make(map[castling.CastlingType]chess.File, 2)
+		for _, castlingType := range [...]castling.CastlingType{castling.TypeShort, castling.TypeLong} {
+			if position, err := castling.RookPosition(castlingType, side, board); err == nil {
+				files[side][castlingType] = position.File
+			}
+		}
+	}
+
+	return files
+}
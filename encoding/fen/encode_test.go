@@ -7,6 +7,7 @@ import (
 	"github.com/elaxer/standardchess/encoding/fen"
 	"github.com/elaxer/standardchess/internal/standardtest"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEncode(t *testing.T) {
@@ -66,3 +67,20 @@ func TestEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeInitial(t *testing.T) {
+	board, err := fen.Decode("r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 5 10")
+	require.NoError(t, err)
+
+	for _, move := range []string{"O-O", "Rxa8", "Rxa8", "O-O"} {
+		_, err = board.MakeMove(move)
+		require.NoError(t, err)
+	}
+
+	current := fen.Encode(board).String()
+
+	initial, err := fen.EncodeInitial(board)
+	require.NoError(t, err)
+	assert.Equal(t, "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 5 10", initial.String())
+	assert.Equal(t, current, fen.Encode(board).String())
+}
@@ -3,8 +3,10 @@ package fen
 
 import (
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/elaxer/chess"
 	"github.com/elaxer/rgx"
@@ -13,14 +15,20 @@ import (
 
 var regexpFEN = regexp.MustCompile(
 	`^(?P<placement>(((1[0-6]|[1-9])|[PNBRQKpnbrqk])+/){5,15}((1[0-6]|[1-9])|[PNBRQKpnbrqk])+)\s` +
-		`(?P<turn>[wb])\s(?P<castlings>-|(K?Q?k?q?))\s(?P<enpassant>-|([a-p](1[0-6]|[1-9])))\s` +
+		`(?P<turn>[wb])\s(?P<castlings>-|(K?Q?k?q?)|([KQA-H]{0,2}[kqa-h]{0,2}))\s(?P<enpassant>-|([a-p](1[0-6]|[1-9])))\s` +
 		`(?P<halfmove_clock>\d+)\s(?P<move_number>\d+)$`,
 )
 
+// FEN is a position in Forsyth–Edwards Notation.
+// The castling rights may be written as in Shredder-FEN or X-FEN for Chess960 positions:
+// by the files of the castling rooks, e.g. "HAha" or "Kkb".
 type FEN struct {
-	placement       string
-	turn            chess.Color
-	castlings       map[chess.Color]map[castling.CastlingType]bool
+	placement string
+	turn      chess.Color
+	castlings map[chess.Color]map[castling.CastlingType]bool
+	// castlingFiles contains the files of the castling rooks given explicitly.
+	// The castling rook of the rights without a file is the outermost rook in the castling direction.
+	castlingFiles   map[chess.Color]map[castling.CastlingType]chess.File
 	enPassantSquare chess.Position
 	halfmoveClock   int
 	moveNumber      int
@@ -41,23 +49,22 @@ func FromString(str string) (FEN, error) {
 		panic(err)
 	}
 
-	return FEN{
+	f := FEN{
 		placement: data["placement"],
 		turn:      color(data["turn"]),
 		castlings: map[chess.Color]map[castling.CastlingType]bool{
-			chess.ColorWhite: {
-				castling.TypeShort: strings.Contains(data["castlings"], "K"),
-				castling.TypeLong:  strings.Contains(data["castlings"], "Q"),
-			},
-			chess.ColorBlack: {
-				castling.TypeShort: strings.Contains(data["castlings"], "k"),
-				castling.TypeLong:  strings.Contains(data["castlings"], "q"),
-			},
+			chess.ColorWhite: {castling.TypeShort: false, castling.TypeLong: false},
+			chess.ColorBlack: {castling.TypeShort: false, castling.TypeLong: false},
 		},
 		enPassantSquare: chess.PositionFromString(data["enpassant"]),
 		halfmoveClock:   halfmoveClock,
 		moveNumber:      moveNumber,
-	}, nil
+	}
+	for _, char := range strings.TrimPrefix(data["castlings"], "-") {
+		f.addCastling(char)
+	}
+
+	return f, nil
 }
 
 func (f FEN) Placement() string {
@@ -72,6 +79,12 @@ func (f FEN) Castlings(side chess.Color) (short, long bool) {
 	return f.castlings[side][castling.TypeShort], f.castlings[side][castling.TypeLong]
 }
 
+// CastlingFiles returns the files of the castling rooks of the side,
+// zero if the side has no right to castle in the direction or the rook isn't found.
+func (f FEN) CastlingFiles(side chess.Color) (short, long chess.File) {
+	return f.castlingFile(side, castling.TypeShort), f.castlingFile(side, castling.TypeLong)
+}
+
 func (f FEN) EnPassantSquare() chess.Position {
 	return f.enPassantSquare
 }
@@ -84,22 +97,28 @@ func (f FEN) MoveNumber() int {
 	return f.moveNumber
 }
 
+// String returns the FEN string.
+// The castling rights are written as in X-FEN:
+// by the file of the castling rook only if it isn't the outermost rook in the castling direction.
 func (f FEN) String() string {
-	castlings := ""
-	if f.castlings[chess.ColorWhite][castling.TypeShort] {
-		castlings += "K"
-	}
-	if f.castlings[chess.ColorWhite][castling.TypeLong] {
-		castlings += "Q"
-	}
-	if f.castlings[chess.ColorBlack][castling.TypeShort] {
-		castlings += "k"
-	}
-	if f.castlings[chess.ColorBlack][castling.TypeLong] {
-		castlings += "q"
+	return f.string(false)
+}
+
+// ShredderString returns the FEN string with the castling rights written as in Shredder-FEN:
+// by the files of the castling rooks, e.g. "HAha".
+func (f FEN) ShredderString() string {
+	return f.string(true)
+}
+
+func (f FEN) string(shredder bool) string {
+	var castlings strings.Builder
+	for _, side := range [...]chess.Color{chess.ColorWhite, chess.ColorBlack} {
+		for _, castlingType := range [...]castling.CastlingType{castling.TypeShort, castling.TypeLong} {
+			castlings.WriteString(f.castlingString(side, castlingType, shredder))
+		}
 	}
-	if castlings == "" {
-		castlings = "-"
+	if castlings.Len() == 0 {
+		castlings.WriteString("-")
 	}
 
 	enPassantSquare := "-"
@@ -107,11 +126,171 @@ func (f FEN) String() string {
 		enPassantSquare = f.EnPassantSquare().String()
 	}
 
-	str := f.placement + " " + f.turn.String() + " " + castlings + " " + enPassantSquare + " "
+	str := f.placement + " " + f.turn.String() + " " + castlings.String() + " " + enPassantSquare + " "
 
 	return str + strconv.Itoa(f.halfmoveClock) + " " + strconv.Itoa(f.moveNumber)
 }
 
+func (f FEN) castlingString(side chess.Color, castlingType castling.CastlingType, shredder bool) string {
+	if !f.castlings[side][castlingType] {
+		return ""
+	}
+
+	str := "K"
+	if castlingType.IsLong() {
+		str = "Q"
+	}
+
+	file := f.castlingFile(side, castlingType)
+	if file != 0 && (shredder || file != f.outermostRookFile(side, castlingType)) {
+		str = string(rune('A' + file - 1))
+	}
+
+	if side.IsBlack() {
+		return strings.ToLower(str)
+	}
+
+	return str
+}
+
+// isChess960 reports whether the castling rights are written by files
+// or belong to a king or a rook which isn't on its square of the standard initial position.
+// Only the boards with 8 files may be Chess960 ones unless the files are given.
+func (f FEN) isChess960() bool {
+	if len(f.castlingFiles) > 0 {
+		return true
+	}
+	if len(f.backRank(chess.ColorWhite)) != int(chess.FileH) {
+		return false
+	}
+
+	for _, side := range [...]chess.Color{chess.ColorWhite, chess.ColorBlack} {
+		short, long := f.Castlings(side)
+		if (short || long) && f.kingFile(side) != chess.FileE {
+			return true
+		}
+		if short && f.outermostRookFile(side, castling.TypeShort) != chess.FileH {
+			return true
+		}
+		if long && f.outermostRookFile(side, castling.TypeLong) != chess.FileA {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addCastling adds the castling right written by the character of the castling field.
+func (f *FEN) addCastling(char rune) {
+	side := chess.ColorWhite
+	if unicode.IsLower(char) {
+		side = chess.ColorBlack
+	}
+
+	switch upper := unicode.ToUpper(char); upper {
+	case 'K':
+		f.castlings[side][castling.TypeShort] = true
+	case 'Q':
+		f.castlings[side][castling.TypeLong] = true
+	default:
+		file := chess.File(upper-'A') + 1
+
+		castlingType := castling.TypeShort
+		if file < f.kingFile(side) {
+			castlingType = castling.TypeLong
+		}
+
+		f.castlings[side][castlingType] = true
+		if f.castlingFiles == nil {
+			f.castlingFiles = make(map[chess.Color]map[castling.CastlingType]chess.File, 2)
+		}
+		if f.castlingFiles[side] == nil {
+			f.castlingFiles[side] = make(map[castling.CastlingType]chess.File, 2)
+		}
+		f.castlingFiles[side][castlingType] = file
+	}
+}
+
+func (f FEN) castlingFile(side chess.Color, castlingType castling.CastlingType) chess.File {
+	if !f.castlings[side][castlingType] {
+		return 0
+	}
+	if file := f.castlingFiles[side][castlingType]; file != 0 {
+		return file
+	}
+
+	return f.outermostRookFile(side, castlingType)
+}
+
+// kingFile returns the file of the king of the side on its back rank, zero if there is no king.
+func (f FEN) kingFile(side chess.Color) chess.File {
+	king := 'K'
+	if side.IsBlack() {
+		king = 'k'
+	}
+
+	return chess.File(slices.Index(f.backRank(side), king) + 1)
+}
+
+// outermostRookFile returns the file of the outermost rook of the side on its back rank
+// in the castling direction from the king, zero if there is no such rook.
+func (f FEN) outermostRookFile(side chess.Color, castlingType castling.CastlingType) chess.File {
+	rook := 'R'
+	if side.IsBlack() {
+		rook = 'r'
+	}
+
+	backRank, kingIndex := f.backRank(side), int(f.kingFile(side))-1
+	if kingIndex < 0 {
+		return 0
+	}
+
+	if castlingType.IsLong() {
+		if i := slices.Index(backRank[:kingIndex], rook); i >= 0 {
+			return chess.File(i + 1)
+		}
+
+		return 0
+	}
+
+	for i := len(backRank) - 1; i > kingIndex; i-- {
+		if backRank[i] == rook {
+			return chess.File(i + 1)
+		}
+	}
+
+	return 0
+}
+
+// backRank returns the squares of the back rank of the side from the a file,
+// the empty squares are zero.
+func (f FEN) backRank(side chess.Color) []rune {
+	rows := strings.Split(f.placement, "/")
+	row := rows[len(rows)-1]
+	if side.IsBlack() {
+		row = rows[0]
+	}
+
+	squares := make([]rune, 0, chess.FileMax)
+	runes := []rune(row)
+	for i := 0; i < len(runes); i++ {
+		if !unicode.IsDigit(runes[i]) {
+			squares = append(squares, runes[i])
+
+			continue
+		}
+
+		empty := int(runes[i] - '0')
+		if i+1 < len(runes) && unicode.IsDigit(runes[i+1]) {
+			empty = empty*10 + int(runes[i+1]-'0')
+			i++
+		}
+		squares = append(squares, make([]rune, empty)...)
+	}
+
+	return squares
+}
+
 func color(str string) chess.Color {
 	switch strings.ToLower(str) {
 	case "w", "":
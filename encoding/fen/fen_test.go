@@ -5,6 +5,7 @@ import (
 
 	"github.com/elaxer/chess"
 	"github.com/elaxer/standardchess/internal/move/castling"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -169,6 +170,10 @@ func TestFEN_String(t *testing.T) {
 			"no_castlings_and_enpassant_target_square",
 			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w - - 0 1",
 		},
+		{
+			"x_fen",
+			"rr2k3/8/8/8/8/8/8/RR2K3 w Qb - 0 1",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -178,3 +183,49 @@ func TestFEN_String(t *testing.T) {
 		})
 	}
 }
+
+func TestFEN_Chess960(t *testing.T) {
+	tests := []struct {
+		name         string
+		str          string
+		wantString   string
+		wantShredder string
+		wantFiles    [2][2]chess.File
+	}{
+		{
+			"standard",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w HAha - 0 1",
+			[2][2]chess.File{{chess.FileH, chess.FileA}, {chess.FileH, chess.FileA}},
+		},
+		{
+			"shredder",
+			"bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9",
+			"bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w KQkq - 2 9",
+			"bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9",
+			[2][2]chess.File{{chess.FileH, chess.FileF}, {chess.FileH, chess.FileF}},
+		},
+		{
+			"inner_rook",
+			"1r1kr2r/8/8/8/8/8/8/1R1KR2R w Eb - 0 1",
+			"1r1kr2r/8/8/8/8/8/8/1R1KR2R w Eq - 0 1",
+			"1r1kr2r/8/8/8/8/8/8/1R1KR2R w Eb - 0 1",
+			[2][2]chess.File{{chess.FileE, 0}, {0, chess.FileB}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := FromString(tt.str)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantString, f.String())
+			assert.Equal(t, tt.wantShredder, f.ShredderString())
+
+			for i, side := range [...]chess.Color{chess.ColorWhite, chess.ColorBlack} {
+				short, long := f.CastlingFiles(side)
+				assert.Equal(t, tt.wantFiles[i], [2]chess.File{short, long})
+			}
+		})
+	}
+}
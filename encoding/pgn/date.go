@@ -0,0 +1,63 @@
+package pgn
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var regexpDate = regexp.MustCompile(`\A(\d{4}|\?{4})\.(\d{2}|\?{2})\.(\d{2}|\?{2})\z`)
+
+// Date is the value of the Date header in the "YYYY.MM.DD" format.
+// The zero parts are unknown, they are written as question marks, e.g. "1992.??.??".
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate returns the date of the time.
+func NewDate(t time.Time) Date {
+	return Date{t.Year(), t.Month(), t.Day()}
+}
+
+// ParseDate parses the date in the "YYYY.MM.DD" format with the unknown parts given by question marks.
+// ErrDecode is returned if the string isn't a valid date.
+func ParseDate(str string) (Date, error) {
+	matches := regexpDate.FindStringSubmatch(str)
+	if matches == nil {
+		return Date{}, fmt.Errorf("%w: invalid date %q", ErrDecode, str)
+	}
+
+	date := Date{datePart(matches[1]), time.Month(datePart(matches[2])), datePart(matches[3])}
+	if date.Month > time.December || date.Day > 31 {
+		return Date{}, fmt.Errorf("%w: invalid date %q", ErrDecode, str)
+	}
+
+	return date, nil
+}
+
+// String returns the date in the "YYYY.MM.DD" format with question marks for the unknown parts.
+func (d Date) String() string {
+	return datePartString(d.Year, "%04d", "????") + "." +
+		datePartString(int(d.Month), "%02d", "??") + "." +
+		datePartString(d.Day, "%02d", "??")
+}
+
+func datePart(str string) int {
+	part, err := strconv.Atoi(str)
+	if err != nil {
+		return 0
+	}
+
+	return part
+}
+
+func datePartString(part int, format, unknown string) string {
+	if part == 0 {
+		return unknown
+	}
+
+	return fmt.Sprintf(format, part)
+}
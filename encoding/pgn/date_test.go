@@ -0,0 +1,48 @@
+package pgn_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    pgn.Date
+		wantErr bool
+	}{
+		{"1992.11.04", pgn.Date{1992, time.November, 4}, false},
+		{"1992.??.??", pgn.Date{Year: 1992}, false},
+		{"????.??.??", pgn.Date{}, false},
+		{"????.03.??", pgn.Date{Month: time.March}, false},
+		{"1992.13.01", pgn.Date{}, true},
+		{"1992.01.32", pgn.Date{}, true},
+		{"1992.1.4", pgn.Date{}, true},
+		{"1992-11-04", pgn.Date{}, true},
+		{"", pgn.Date{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			got, err := pgn.ParseDate(tt.str)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, pgn.ErrDecode)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.str, got.String())
+		})
+	}
+}
+
+func TestNewDate(t *testing.T) {
+	date := pgn.NewDate(time.Date(2017, time.August, 14, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "2017.08.14", date.String())
+}
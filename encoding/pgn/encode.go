@@ -1,13 +1,48 @@
 package pgn
 
 import (
-	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/eco"
 )
 
-func Encode(headers Headers, board chess.Board, result Result) PGN {
+// MaxLineLength is the maximum length of the movetext lines of the PGN export format.
+const MaxLineLength = 255
+
+// fenFieldsCount is the number of the fields of a FEN string.
+const fenFieldsCount = 6
+
+// EncodeOption configures Encode.
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+	opening bool
+}
+
+// movetextWriter collects the tokens of the movetext.
+// The parentheses of the variations are joined with the adjacent tokens.
+type movetextWriter struct {
+	tokens            []string
+	isVariationOpened bool
+}
+
+// Encode creates the PGN of the game played on the board.
+// The Variant, SetUp and FEN headers are added if the game is Chess960
+// or isn't started from the standard initial position, unless they are already given.
+func Encode(headers Headers, board chess.Board, result Result, opts ...EncodeOption) PGN {
+	var options encodeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	headers = append(slices.Clone(headers), setUpHeaders(headers, board)...)
+	if options.opening {
+		headers = append(headers, openingHeaders(headers, board)...)
+	}
+
 	moves := make([]string, 0, len(board.MoveHistory()))
 	for _, move := range board.MoveHistory() {
 		moves = append(moves, move.String())
@@ -16,6 +51,15 @@ func Encode(headers Headers, board chess.Board, result Result) PGN {
 	return NewPGN(headers, moves, result)
 }
 
+// WithOpening makes Encode add the ECO, Opening and Variation headers of the opening of the game,
+// see eco.Classify, unless they are already given.
+// No headers are added if the opening is unknown, the Variation header is added only if there is a variation.
+func WithOpening() EncodeOption {
+	return func(options *encodeOptions) {
+		options.opening = true
+	}
+}
+
 func encodeHeaders(headers Headers) string {
 	headerStrings := make([]string, 0, len(headers))
 	for _, header := range headers {
@@ -25,27 +69,121 @@ func encodeHeaders(headers Headers) string {
 	return strings.Join(headerStrings, "\n")
 }
 
-func encodeMoves(moves []string) string {
-	var str strings.Builder
-	currentMoveNumber := 0
-	for i, move := range moves {
-		if moveNumber := (i + 2) / 2; moveNumber != currentMoveNumber {
-			currentMoveNumber = moveNumber
-			fmt.Fprintf(&str, "%d. ", currentMoveNumber)
+// openingHeaders returns the headers of the opening of the game played on the board which are missing in the headers.
+func openingHeaders(headers Headers, board chess.Board) Headers {
+	opening, ok := eco.Classify(board)
+	if !ok {
+		return Headers{}
+	}
+
+	missing := make(Headers, 0, 3)
+	for _, header := range [...]Header{
+		NewHeader(HeaderECO, opening.ECO),
+		NewHeader(HeaderOpening, opening.Family()),
+		NewHeader(HeaderVariation, opening.Variation()),
+	} {
+		if _, ok := headers.Get(header.Name); !ok && header.Value != "" {
+			missing = append(missing, header)
 		}
+	}
+
+	return missing
+}
+
+// encodeMovetext returns the movetext of the game tree started at the ply followed by the result.
+func encodeMovetext(root *Node, startPly int, result Result) string {
+	w := &movetextWriter{}
+	w.writeComments(root.Comments)
+	if next := root.Next(); next != nil {
+		w.writeLine(next, startPly)
+	}
+	w.write(string(result))
+
+	return strings.Join(w.tokens, " ")
+}
 
-		if i == len(moves)-1 {
-			str.WriteString(move)
+// writeLine writes the moves of the line started at the node with their annotations and variations.
+func (w *movetextWriter) writeLine(node *Node, ply int) {
+	forceNumber := true
+	for ; node != nil; node, ply = node.Next(), ply+1 {
+		w.writeMove(node, ply, forceNumber)
+		forceNumber = len(node.Comments) > 0
 
-			break
+		if node.parent.Next() != node {
+			continue
 		}
+		for _, variation := range node.parent.children[1:] {
+			w.isVariationOpened = true
+			w.writeLine(variation, ply)
+			w.tokens[len(w.tokens)-1] += ")"
+			forceNumber = true
+		}
+	}
+}
+
+// writeMove writes the move of the node with its number, NAGs and comments.
+// The number of the black's move is written only if forceNumber is true, e.g. after a comment or a variation.
+func (w *movetextWriter) writeMove(node *Node, ply int, forceNumber bool) {
+	w.writeComments(node.StartingComments)
+
+	switch moveNumber := ply/2 + 1; {
+	case ply%2 == 0:
+		w.write(strconv.Itoa(moveNumber) + ".")
+	case forceNumber || len(node.StartingComments) > 0:
+		w.write(strconv.Itoa(moveNumber) + "...")
+	}
+
+	w.write(node.Move)
+	for _, nag := range node.NAGs {
+		w.write("$" + strconv.Itoa(nag))
+	}
+	w.writeComments(node.Comments)
+}
+
+func (w *movetextWriter) writeComments(comments []string) {
+	for _, comment := range comments {
+		w.write("{" + strings.Join(strings.Fields(comment), " ") + "}")
+	}
+}
+
+func (w *movetextWriter) write(token string) {
+	if w.isVariationOpened {
+		token = "(" + token
+		w.isVariationOpened = false
+	}
+
+	w.tokens = append(w.tokens, token)
+}
+
+// startingPly returns the number of the plies played before the initial position of the game,
+// which is given by the FEN header, or zero if there is no such header.
+func startingPly(headers Headers) int {
+	fenHeader, ok := headers.Get(HeaderFEN)
+	if !ok {
+		return 0
+	}
+
+	fields := strings.Fields(fenHeader.Value)
+	if len(fields) < fenFieldsCount {
+		return 0
+	}
+
+	moveNumber, err := strconv.Atoi(fields[fenFieldsCount-1])
+	if err != nil || moveNumber < 1 {
+		return 0
+	}
 
-		str.WriteString(move + " ")
+	ply := (moveNumber - 1) * 2
+	if fields[1] == "b" {
+		ply++
 	}
 
-	return str.String()
+	return ply
 }
 
+// wrapText wraps the text so that its lines aren't longer than maxWidth,
+// a word longer than maxWidth is written on its own line.
+// The text isn't wrapped if maxWidth isn't positive.
 func wrapText(text string, maxWidth int) string {
 	if maxWidth <= 0 {
 		return text
@@ -57,7 +195,7 @@ func wrapText(text string, maxWidth int) string {
 	words := strings.Fields(text)
 
 	for i, word := range words {
-		if lineLen+len(word) > maxWidth {
+		if i != 0 && lineLen+1+len(word) > maxWidth {
 			result.WriteString("\n")
 			lineLen = 0
 		} else if i != 0 {
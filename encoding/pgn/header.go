@@ -1,6 +1,12 @@
 package pgn
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+// headerValueEscaper escapes the quotes and the backslashes of the header values.
+var headerValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
 
 // Header represents a PGN header with a name and value.
 // It is used to store metadata about the chess game, such as event, site, date, etc.
@@ -17,7 +23,8 @@ func NewHeader(name, value string) Header {
 }
 
 // String formats the header as a PGN string.
-// It returns a string in the format "[name "value"]".
+// It returns a string in the format "[name "value"]",
+// the quotes and the backslashes of the value are escaped by the backslash.
 func (h Header) String() string {
-	return fmt.Sprintf("[%s \"%s\"]", h.Name, h.Value)
+	return fmt.Sprintf("[%s \"%s\"]", h.Name, headerValueEscaper.Replace(h.Value))
 }
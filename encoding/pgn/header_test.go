@@ -36,6 +36,11 @@ func TestHeader_String(t *testing.T) {
 			fields{"", ""},
 			`[ ""]`,
 		},
+		{
+			"escaped",
+			fields{"Annotator", `The "Best" \ Worst`},
+			`[Annotator "The \"Best\" \\ Worst"]`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,5 +1,57 @@
 package pgn
 
+import (
+	"fmt"
+	"slices"
+)
+
+// The names of the Seven Tag Roster headers which are required by the PGN export format.
+const (
+	HeaderEvent  = "Event"
+	HeaderSite   = "Site"
+	HeaderDate   = "Date"
+	HeaderRound  = "Round"
+	HeaderWhite  = "White"
+	HeaderBlack  = "Black"
+	HeaderResult = "Result"
+)
+
+// The names of the headers with the ratings of the players.
+const (
+	HeaderWhiteElo = "WhiteElo"
+	HeaderBlackElo = "BlackElo"
+)
+
+// The names of the headers with the opening of the game.
+const (
+	HeaderECO       = "ECO"
+	HeaderOpening   = "Opening"
+	HeaderVariation = "Variation"
+)
+
+// HeaderTimeControl is the name of the header with the time control of the game, e.g. "40/7200:3600".
+const HeaderTimeControl = "TimeControl"
+
+// HeaderTermination is the name of the header with the reason of the end of the game, e.g. "time forfeit".
+const HeaderTermination = "Termination"
+
+// The values of the Termination header, TerminationUnterminated is used for the games which aren't finished.
+const (
+	TerminationAbandoned       = "abandoned"
+	TerminationAdjudication    = "adjudication"
+	TerminationDeath           = "death"
+	TerminationEmergency       = "emergency"
+	TerminationNormal          = "normal"
+	TerminationRulesInfraction = "rules infraction"
+	TerminationTimeForfeit     = "time forfeit"
+	TerminationUnterminated    = "unterminated"
+)
+
+// sevenTagRoster is the list of the Seven Tag Roster header names in the order of the PGN export format.
+var sevenTagRoster = [...]string{
+	HeaderEvent, HeaderSite, HeaderDate, HeaderRound, HeaderWhite, HeaderBlack, HeaderResult,
+}
+
 type Headers []Header
 
 func (h Headers) Get(name string) (Header, bool) {
@@ -11,3 +63,98 @@ func (h Headers) Get(name string) (Header, bool) {
 
 	return Header{}, false
 }
+
+// Value returns the value of the header with the name, an empty string if there is no such header.
+func (h Headers) Value(name string) string {
+	header, _ := h.Get(name)
+
+	return header.Value
+}
+
+// Set sets the value of the header with the name, the header is appended if there is no such header.
+func (h *Headers) Set(name, value string) {
+	if i := slices.IndexFunc(*h, func(header Header) bool { return header.Name == name }); i >= 0 {
+		(*h)[i].Value = value
+
+		return
+	}
+
+	*h = append(*h, NewHeader(name, value))
+}
+
+// Delete deletes the headers with the name.
+func (h *Headers) Delete(name string) {
+	*h = slices.DeleteFunc(*h, func(header Header) bool { return header.Name == name })
+}
+
+// Sorted returns the headers with the Seven Tag Roster ones first and in their order.
+// The other headers keep their order.
+func (h Headers) Sorted() Headers {
+	sorted := slices.Clone(h)
+	slices.SortStableFunc(sorted, func(a, b Header) int {
+		return sevenTagRosterIndex(a.Name) - sevenTagRosterIndex(b.Name)
+	})
+
+	return sorted
+}
+
+// Event returns the value of the Event header.
+func (h Headers) Event() string {
+	return h.Value(HeaderEvent)
+}
+
+// Site returns the value of the Site header.
+func (h Headers) Site() string {
+	return h.Value(HeaderSite)
+}
+
+// Date returns the value of the Date header.
+// ErrDecode is returned if there is no such header or its value isn't a valid date.
+func (h Headers) Date() (Date, error) {
+	header, ok := h.Get(HeaderDate)
+	if !ok {
+		return Date{}, fmt.Errorf("%w: no %s header", ErrDecode, HeaderDate)
+	}
+
+	return ParseDate(header.Value)
+}
+
+// Round returns the value of the Round header.
+func (h Headers) Round() string {
+	return h.Value(HeaderRound)
+}
+
+// White returns the value of the White header.
+func (h Headers) White() string {
+	return h.Value(HeaderWhite)
+}
+
+// Black returns the value of the Black header.
+func (h Headers) Black() string {
+	return h.Value(HeaderBlack)
+}
+
+// Result returns the value of the Result header.
+func (h Headers) Result() Result {
+	return Result(h.Value(HeaderResult))
+}
+
+// SetDate sets the value of the Date header.
+func (h *Headers) SetDate(date Date) {
+	h.Set(HeaderDate, date.String())
+}
+
+// SetResult sets the value of the Result header.
+func (h *Headers) SetResult(result Result) {
+	h.Set(HeaderResult, string(result))
+}
+
+// sevenTagRosterIndex returns the index of the header name in the Seven Tag Roster,
+// the length of the Seven Tag Roster if it isn't there.
+func sevenTagRosterIndex(name string) int {
+	if i := slices.Index(sevenTagRoster[:], name); i >= 0 {
+		return i
+	}
+
+	return len(sevenTagRoster)
+}
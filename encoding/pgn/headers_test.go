@@ -5,6 +5,7 @@ import (
 
 	"github.com/elaxer/standardchess/encoding/pgn"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHeaders_Get(t *testing.T) {
@@ -32,3 +33,79 @@ func TestHeaders_Get(t *testing.T) {
 	assert.Equal(t, pgn.Header{}, unknown)
 	assert.False(t, ok)
 }
+
+func TestHeaders_Set(t *testing.T) {
+	headers := pgn.Headers{pgn.NewHeader("Event", "Match"), pgn.NewHeader("ECO", "C60")}
+
+	headers.Set("Event", "Tournament")
+	headers.Set("White", "Kasparov")
+
+	assert.Equal(t, pgn.Headers{
+		pgn.NewHeader("Event", "Tournament"),
+		pgn.NewHeader("ECO", "C60"),
+		pgn.NewHeader("White", "Kasparov"),
+	}, headers)
+}
+
+func TestHeaders_Delete(t *testing.T) {
+	headers := pgn.Headers{pgn.NewHeader("Event", "Match"), pgn.NewHeader("ECO", "C60"), pgn.NewHeader("Event", "")}
+
+	headers.Delete("Event")
+	headers.Delete("Unknown")
+
+	assert.Equal(t, pgn.Headers{pgn.NewHeader("ECO", "C60")}, headers)
+}
+
+func TestHeaders_Sorted(t *testing.T) {
+	headers := pgn.Headers{
+		pgn.NewHeader("ECO", "C60"),
+		pgn.NewHeader("Result", "1-0"),
+		pgn.NewHeader("White", "Kasparov"),
+		pgn.NewHeader("Annotator", "?"),
+		pgn.NewHeader("Event", "Match"),
+	}
+
+	assert.Equal(t, pgn.Headers{
+		pgn.NewHeader("Event", "Match"),
+		pgn.NewHeader("White", "Kasparov"),
+		pgn.NewHeader("Result", "1-0"),
+		pgn.NewHeader("ECO", "C60"),
+		pgn.NewHeader("Annotator", "?"),
+	}, headers.Sorted())
+	assert.Equal(t, pgn.NewHeader("ECO", "C60"), headers[0])
+}
+
+func TestHeaders_SevenTagRoster(t *testing.T) {
+	headers := pgn.Headers{}
+	headers.Set(pgn.HeaderEvent, "It (open)")
+	headers.Set(pgn.HeaderSite, "Sevilla (Spain)")
+	headers.SetDate(pgn.Date{Year: 1992})
+	headers.Set(pgn.HeaderRound, "?")
+	headers.Set(pgn.HeaderWhite, "Gonzalez Raul")
+	headers.Set(pgn.HeaderBlack, "Mikhail Tal")
+	headers.SetResult(pgn.ResultWinBlack)
+
+	assert.Equal(t, "It (open)", headers.Event())
+	assert.Equal(t, "Sevilla (Spain)", headers.Site())
+	assert.Equal(t, "?", headers.Round())
+	assert.Equal(t, "Gonzalez Raul", headers.White())
+	assert.Equal(t, "Mikhail Tal", headers.Black())
+	assert.Equal(t, pgn.ResultWinBlack, headers.Result())
+	assert.Equal(t, "1992.??.??", headers.Value(pgn.HeaderDate))
+
+	date, err := headers.Date()
+	require.NoError(t, err)
+	assert.Equal(t, pgn.Date{Year: 1992}, date)
+
+	_, err = pgn.Headers{}.Date()
+	assert.ErrorIs(t, err, pgn.ErrDecode)
+}
+
+func TestHeaders_Escaping(t *testing.T) {
+	headers := pgn.Headers{pgn.NewHeader("Annotator", `The "Best" \ Worst`), pgn.NewHeader("Event", `\"`)}
+
+	p, err := pgn.FromString(pgn.NewPGN(headers, []string{"e4"}, pgn.ResultInProcess).String())
+	require.NoError(t, err)
+
+	assert.Equal(t, headers, p.Headers())
+}
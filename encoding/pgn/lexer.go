@@ -0,0 +1,287 @@
+package pgn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	tokenEOF tokenKind = iota
+	tokenSymbol
+	tokenString
+	tokenComment
+	tokenNAG
+	tokenAnnotation
+	tokenPeriod
+	tokenOpen
+	tokenClose
+	tokenTagOpen
+	tokenTagClose
+)
+
+// byteOrderMark is the UTF-8 byte order mark which is skipped by the lexer.
+const byteOrderMark = "\xef\xbb\xbf"
+
+var punctuation = map[byte]tokenKind{
+	'.': tokenPeriod,
+	'(': tokenOpen,
+	')': tokenClose,
+	'[': tokenTagOpen,
+	']': tokenTagClose,
+	'*': tokenSymbol,
+}
+
+// SyntaxError is the error of decoding PGN data with the position where it's found.
+// It wraps ErrDecode.
+type SyntaxError struct {
+	// Offset is the byte offset of the error position from the start of the data.
+	Offset int64
+	// Line is the line number of the error position starting at 1.
+	Line int
+
+	err error
+}
+
+type tokenKind int
+
+type token struct {
+	kind  tokenKind
+	value string
+
+	offset int64
+	line   int
+	// isLineStart is true if the token starts at the beginning of a line.
+	isLineStart bool
+}
+
+// lexer reads the tokens of PGN data from a reader, keeping track of the position.
+// The escaped lines starting with "%" are skipped.
+type lexer struct {
+	r *bufio.Reader
+
+	offset      int64
+	line        int
+	isLineStart bool
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, offset %d: %s", e.Line, e.Offset, e.err)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.err
+}
+
+func newLexer(r io.Reader) *lexer {
+	return &lexer{r: bufio.NewReader(r), line: 1, isLineStart: true}
+}
+
+func (l *lexer) next() (token, error) {
+	if err := l.skipSpaces(); err != nil && !errors.Is(err, io.EOF) {
+		return token{}, err
+	}
+
+	tok := token{offset: l.offset, line: l.line, isLineStart: l.isLineStart}
+	c, err := l.read()
+	if errors.Is(err, io.EOF) {
+		return tok, nil
+	}
+	if err != nil {
+		return token{}, err
+	}
+
+	switch {
+	case c == '{':
+		tok.kind = tokenComment
+		tok.value, err = l.readComment(tok)
+		tok.value = strings.TrimSpace(tok.value)
+	case c == ';':
+		tok.kind = tokenComment
+		tok.value, err = l.readWhile(func(c byte) bool { return c != '\n' })
+		tok.value = strings.TrimSpace(tok.value)
+	case c == '"':
+		tok.kind = tokenString
+		tok.value, err = l.readString(tok)
+	case c == '$':
+		tok.kind = tokenNAG
+		tok.value, err = l.readWhile(isDigit)
+	case c == '!' || c == '?':
+		tok.kind = tokenAnnotation
+		tok.value, err = l.readWhile(isAnnotationChar)
+		tok.value = string(c) + tok.value
+	case isDigit(c) || isLetter(c):
+		tok.kind = tokenSymbol
+		tok.value, err = l.readWhile(isSymbolChar)
+		tok.value = string(c) + tok.value
+	default:
+		kind, ok := punctuation[c]
+		if !ok {
+			return token{}, newSyntaxError(tok, "unexpected character %q", c)
+		}
+		tok.kind, tok.value = kind, string(c)
+	}
+	if err != nil {
+		return token{}, err
+	}
+
+	return tok, nil
+}
+
+// hasPrefix reports whether the unread data starts with the prefix.
+func (l *lexer) hasPrefix(prefix string) (bool, error) {
+	b, err := l.r.Peek(len(prefix))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+
+	return string(b) == prefix, nil
+}
+
+// skipSpaces skips the white spaces, the byte order marks and the escaped lines.
+func (l *lexer) skipSpaces() error {
+	for {
+		c, err := l.peek()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			_, err = l.read()
+		case c == '%' && l.isLineStart:
+			err = l.skipLine()
+		case c == byteOrderMark[0]:
+			if ok, err := l.hasPrefix(byteOrderMark); !ok || err != nil {
+				return err
+			}
+
+			isLineStart := l.isLineStart
+			_, err = l.r.Discard(len(byteOrderMark))
+			l.offset += int64(len(byteOrderMark))
+			l.isLineStart = isLineStart
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// skipLine skips the rest of the current line including the line break.
+func (l *lexer) skipLine() error {
+	for {
+		c, err := l.read()
+		if err != nil || c == '\n' {
+			return err
+		}
+	}
+}
+
+func (l *lexer) readComment(tok token) (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := l.read()
+		if errors.Is(err, io.EOF) {
+			return "", newSyntaxError(tok, "unterminated comment")
+		}
+		if err != nil {
+			return "", err
+		}
+		if c == '}' {
+			return sb.String(), nil
+		}
+
+		sb.WriteByte(c)
+	}
+}
+
+// readString reads the string token, the quotes and the backslashes in it are escaped by the backslash.
+func (l *lexer) readString(tok token) (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := l.read()
+		if errors.Is(err, io.EOF) || c == '\n' {
+			return "", newSyntaxError(tok, "unterminated string")
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if next, err := l.peek(); err == nil && (next == '"' || next == '\\') {
+				c, _ = l.read()
+			}
+		}
+
+		sb.WriteByte(c)
+	}
+}
+
+func (l *lexer) readWhile(f func(c byte) bool) (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := l.peek()
+		if errors.Is(err, io.EOF) || (err == nil && !f(c)) {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		_, _ = l.read()
+		sb.WriteByte(c)
+	}
+}
+
+func (l *lexer) read() (byte, error) {
+	c, err := l.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	l.offset++
+	l.isLineStart = c == '\n'
+	if l.isLineStart {
+		l.line++
+	}
+
+	return c, nil
+}
+
+func (l *lexer) peek() (byte, error) {
+	b, err := l.r.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
+
+func newSyntaxError(tok token, format string, args ...any) error {
+	err := fmt.Errorf("%w: "+format, append([]any{ErrDecode}, args...)...)
+
+	return &SyntaxError{Offset: tok.offset, Line: tok.line, err: err}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isSymbolChar(c byte) bool {
+	return isDigit(c) || isLetter(c) || strings.IndexByte("_+#=:-/", c) >= 0
+}
+
+func isAnnotationChar(c byte) bool {
+	return c == '!' || c == '?'
+}
@@ -0,0 +1,106 @@
+package pgn_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromString_Annotations(t *testing.T) {
+	got, err := pgn.FromString(`[Event "Annotated"]
+
+{Opening comment} 1. e4! $14 {Best by test} (1. d4 d5 (1... Nf6 2. c4) 2. c4) (1. c4)
+1... e5?! ; the main line
+2. Nf3 ({Instead} 2. f4 exf4 {gambit}) Nc6 (2... Nf6 $2) *`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"e4", "e5", "Nf3", "Nc6"}, got.Moves())
+	assert.Equal(t, pgn.ResultInProcess, got.Result())
+
+	root := got.Root()
+	require.True(t, root.IsRoot())
+	assert.Equal(t, []string{"Opening comment"}, root.Comments)
+	require.Len(t, root.Children(), 3)
+
+	e4, d4, c4 := root.Children()[0], root.Children()[1], root.Children()[2]
+	assert.Equal(t, "e4", e4.Move)
+	assert.Equal(t, []int{pgn.NAGGoodMove, 14}, e4.NAGs)
+	assert.Equal(t, []string{"Best by test"}, e4.Comments)
+	assert.Equal(t, "d4", d4.Move)
+	assert.Equal(t, "c4", c4.Move)
+	assert.Empty(t, c4.Children())
+
+	require.Len(t, d4.Children(), 2)
+	assert.Equal(t, []string{"d5", "c4"}, moves(d4.Mainline()))
+	assert.Equal(t, []string{"Nf6", "c4"}, moves(append([]*pgn.Node{d4.Children()[1]}, d4.Children()[1].Mainline()...)))
+
+	e5 := e4.Next()
+	assert.Equal(t, []int{pgn.NAGDubiousMove}, e5.NAGs)
+	assert.Equal(t, []string{"the main line"}, e5.Comments)
+	require.Len(t, e5.Children(), 2)
+
+	f4 := e5.Children()[1]
+	assert.Equal(t, []string{"Instead"}, f4.StartingComments)
+	assert.Equal(t, []string{"gambit"}, f4.Next().Comments)
+	assert.Same(t, e5, f4.Parent())
+
+	nc6 := e5.Next().Next()
+	require.Len(t, e5.Next().Children(), 2)
+	assert.Equal(t, []int{pgn.NAGMistake}, e5.Next().Children()[1].NAGs)
+	assert.Nil(t, nc6.Next())
+}
+
+func TestFromString_MovesInComments(t *testing.T) {
+	got, err := pgn.FromString(`1. e4 {1... c5 is the Sicilian} e5 ; 2. Nf3
+% 2. d4 is escaped
+2. Bc4 1-0`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"e4", "e5", "Bc4"}, got.Moves())
+	assert.Equal(t, pgn.ResultWinWhite, got.Result())
+}
+
+func TestFromString_MovetextErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		movetext string
+	}{
+		{"unterminated_comment", "1. e4 {comment *"},
+		{"unterminated_variation", "1. e4 (1. d4 *"},
+		{"unexpected_close", "1. e4 ) *"},
+		{"variation_before_move", "(1. d4) 1. e4 *"},
+		{"nag_before_move", "$1 1. e4 *"},
+		{"invalid_nag", "1. e4 $256 *"},
+		{"invalid_annotation", "1. e4 ?!? *"},
+		{"result_in_variation", "1. e4 (1. d4 *) *"},
+		{"invalid_move", "1. e4 e9 *"},
+		{"unexpected_character", "1. e4 & *"},
+		{"no_moves", "{comment} *"},
+		{"no_result", "1. e4 e5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pgn.FromString(tt.movetext)
+			assert.ErrorIs(t, err, pgn.ErrDecode)
+		})
+	}
+}
+
+func TestNewPGN(t *testing.T) {
+	p := pgn.NewPGN(pgn.Headers{}, []string{"e4", "e5"}, pgn.ResultInProcess)
+
+	assert.Equal(t, []string{"e4", "e5"}, p.Moves())
+	assert.Equal(t, "e4", p.Root().Next().Move)
+	assert.Equal(t, "1. e4 e5 *", p.Format(0))
+}
+
+func moves(nodes []*pgn.Node) []string {
+	moves := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		moves = append(moves, node.Move)
+	}
+
+	return moves
+}
@@ -1,51 +1,277 @@
 package pgn
 
 import (
-	"bufio"
+	"errors"
 	"io"
 	"iter"
-	"strings"
+	"strconv"
 )
 
-// Parse reads PGN data from the provided io.Reader and returns a slice of PGN games.
-// The reader can be any type implementing io.Reader, such as a file, buffer, or network stream.
-// The function splits the input into individual PGN games using empty lines as separators.
-// Each game is returned as a PGN object containing headers, moves, and the result.
-// Returns a slice of PGN games and an error if reading or parsing fails.
-func Parse(r io.Reader) iter.Seq2[PGN, error] {
-	scanner := bufio.NewScanner(r)
-	var pgnStr strings.Builder
+// maxNAG is the greatest Numeric Annotation Glyph.
+const maxNAG = 255
 
-	wasLineBreak := false
+// parser builds the games from the tokens of PGN data.
+type parser struct {
+	lexer  *lexer
+	peeked *token
+}
 
+// Parse reads PGN games from the provided io.Reader one at a time.
+// The reader can be any type implementing io.Reader, such as a file, buffer, or network stream.
+// The games are split by their tokens, so the blank lines, line lengths and the byte order mark don't matter.
+// Games without moves are allowed.
+//
+// A malformed game is yielded as a *SyntaxError with the byte offset and the line number of the problem,
+// the parsing is continued then from the next line starting with the "[Event" tag.
+// The iteration is stopped after the error of the reader.
+func Parse(r io.Reader) iter.Seq2[PGN, error] {
 	return func(yield func(PGN, error) bool) {
-		for scanner.Scan() {
-			line := scanner.Text()
+		p := newParser(r)
+		for {
+			game, err := p.parseGame()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(game, err) {
+				return
+			}
+			if err == nil {
+				continue
+			}
 
-			if strings.TrimSpace(line) != "" {
-				pgnStr.WriteString(line + "\n")
+			if syntaxErr := new(SyntaxError); !errors.As(err, &syntaxErr) {
+				return
+			}
+			if err := p.recover(); err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(PGN{}, err)
+				}
 
-				continue
+				return
 			}
+		}
+	}
+}
 
-			if pgnStr.Len() == 0 {
-				continue
+func newParser(r io.Reader) *parser {
+	return &parser{lexer: newLexer(r)}
+}
+
+// parseGame parses the next game, io.EOF is returned if there are no more games.
+func (p *parser) parseGame() (PGN, error) {
+	tok, err := p.peek()
+	if err != nil {
+		return PGN{}, err
+	}
+	if tok.kind == tokenEOF {
+		return PGN{}, io.EOF
+	}
+
+	headers, err := p.parseHeaders()
+	if err != nil {
+		return PGN{}, err
+	}
+
+	root := NewNode()
+	result, err := p.parseLine(root, false)
+	if err != nil {
+		return PGN{}, err
+	}
+
+	return PGN{headers, root, result}, nil
+}
+
+func (p *parser) parseHeaders() (Headers, error) {
+	headers := make(Headers, 0)
+	for {
+		tok, err := p.peek()
+		if err != nil || tok.kind != tokenTagOpen {
+			return headers, err
+		}
+		p.peeked = nil
+
+		name, err := p.expect(tokenSymbol, "tag name")
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.expect(tokenString, "tag value")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenTagClose, "\"]\""); err != nil {
+			return nil, err
+		}
+
+		headers = append(headers, NewHeader(name.value, value.value))
+	}
+}
+
+// parseLine parses the moves played after the move of the start node
+// until the end of the variation or the termination marker of the game.
+func (p *parser) parseLine(start *Node, isVariation bool) (Result, error) {
+	current := start
+	var startingComments []string
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return "", err
+		}
+
+		switch tok.kind {
+		case tokenPeriod:
+		case tokenComment:
+			if isVariation && current == start {
+				startingComments = append(startingComments, tok.value)
+			} else {
+				current.Comments = append(current.Comments, tok.value)
+			}
+		case tokenNAG, tokenAnnotation:
+			if err := addNAG(current, start, tok); err != nil {
+				return "", err
+			}
+		case tokenOpen:
+			if current == start {
+				return "", newSyntaxError(tok, "variation before a move")
+			}
+			if _, err := p.parseLine(current.parent, true); err != nil {
+				return "", err
+			}
+		case tokenClose:
+			if !isVariation {
+				return "", newSyntaxError(tok, "unexpected \")\"")
 			}
 
-			if !wasLineBreak {
-				wasLineBreak = true
-				pgnStr.WriteString("\n")
+			return "", nil
+		case tokenEOF, tokenTagOpen, tokenTagClose, tokenString:
+			return "", p.unexpected(tok, isVariation)
+		case tokenSymbol:
+			if result := Result(tok.value); result.isTerminationMarker() {
+				if isVariation {
+					return "", newSyntaxError(tok, "game termination marker in a variation")
+				}
 
+				return result, nil
+			}
+			if isMoveNumber(tok.value) {
 				continue
 			}
+			if !regexpMove.MatchString(tok.value) {
+				return "", newSyntaxError(tok, "invalid move %q", tok.value)
+			}
 
-			pgn, err := FromString(pgnStr.String())
-			if !yield(pgn, err) {
-				return
+			current = current.AddMove(tok.value)
+			current.StartingComments, startingComments = startingComments, nil
+		}
+	}
+}
+
+// recover skips the rest of the malformed game until the line starting with the "[Event" tag.
+func (p *parser) recover() error {
+	const eventTag = "[" + HeaderEvent
+
+	if tok := p.peeked; tok != nil && tok.kind == tokenTagOpen && tok.isLineStart {
+		if ok, err := p.lexer.hasPrefix(HeaderEvent); ok || err != nil {
+			return err
+		}
+	}
+	p.peeked = nil
+
+	for {
+		if !p.lexer.isLineStart {
+			if err := p.lexer.skipLine(); err != nil {
+				return err
 			}
+		}
 
-			wasLineBreak = false
-			pgnStr.Reset()
+		ok, err := p.lexer.hasPrefix(eventTag)
+		if ok || err != nil {
+			return err
 		}
+		p.lexer.isLineStart = false
 	}
 }
+
+// unexpected returns the error of the token which can't be in the movetext.
+// The token is left unread if it's the start of the next game.
+func (p *parser) unexpected(tok token, isVariation bool) error {
+	switch {
+	case isVariation:
+		return newSyntaxError(tok, "unterminated variation")
+	case tok.kind == tokenEOF || tok.kind == tokenTagOpen:
+		p.peeked = &tok
+
+		return newSyntaxError(tok, "no game termination marker")
+	default:
+		return newSyntaxError(tok, "unexpected %q", tok.value)
+	}
+}
+
+func (p *parser) expect(kind tokenKind, name string) (token, error) {
+	tok, err := p.next()
+	if err != nil {
+		return token{}, err
+	}
+	if tok.kind != kind {
+		return token{}, newSyntaxError(tok, "%s expected", name)
+	}
+
+	return tok, nil
+}
+
+func (p *parser) next() (token, error) {
+	if tok := p.peeked; tok != nil {
+		p.peeked = nil
+
+		return *tok, nil
+	}
+
+	return p.lexer.next()
+}
+
+func (p *parser) peek() (token, error) {
+	if p.peeked == nil {
+		tok, err := p.lexer.next()
+		if err != nil {
+			return token{}, err
+		}
+		p.peeked = &tok
+	}
+
+	return *p.peeked, nil
+}
+
+// addNAG adds the NAG or the suffix annotation of the token to the node
+// unless the node is the start node of the line, which means there is no move to annotate.
+func addNAG(node, start *Node, tok token) error {
+	if node == start {
+		return newSyntaxError(tok, "annotation %q before a move", tok.value)
+	}
+
+	if tok.kind == tokenAnnotation {
+		nag, ok := suffixAnnotations[tok.value]
+		if !ok {
+			return newSyntaxError(tok, "invalid annotation %q", tok.value)
+		}
+		node.NAGs = append(node.NAGs, nag)
+
+		return nil
+	}
+
+	nag, err := strconv.Atoi(tok.value)
+	if err != nil || nag > maxNAG {
+		return newSyntaxError(tok, "invalid NAG %q", "$"+tok.value)
+	}
+	node.NAGs = append(node.NAGs, nag)
+
+	return nil
+}
+
+func isMoveNumber(str string) bool {
+	for _, c := range []byte(str) {
+		if !isDigit(c) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,6 +1,8 @@
 package pgn_test
 
 import (
+	"errors"
+	"io"
 	"strconv"
 	"strings"
 	"testing"
@@ -10,6 +12,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var errRead = errors.New("read error")
+
+type failingReader struct{}
+
 func TestParse(t *testing.T) {
 	reader := strings.NewReader(pgnStr)
 
@@ -25,6 +31,125 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, len(expected), i-1)
 }
 
+func TestParse_RealWorld(t *testing.T) {
+	longComment := strings.Repeat("a", 100_000)
+	data := "\xef\xbb\xbf[Event \"First\"]\n[Site \"?\"]\n1. e4 e5 *\n" +
+		"[Event \"Second\"]\r\n\r\n\r\n\r\n1. d4 {A comment\n\nwith blank lines} d5 1-0\n\n\n" +
+		"1. c4 {" + longComment + "} 0-1 " +
+		"[Event \"Empty\"] [Result \"1-0\"] 1-0"
+
+	var games []pgn.PGN
+	for p, err := range pgn.Parse(strings.NewReader(data)) {
+		require.NoError(t, err)
+		games = append(games, p)
+	}
+
+	require.Len(t, games, 4)
+	assert.Equal(t, pgn.Headers{pgn.NewHeader("Event", "First"), pgn.NewHeader("Site", "?")}, games[0].Headers())
+	assert.Equal(t, []string{"e4", "e5"}, games[0].Moves())
+	assert.Equal(t, []string{"d4", "d5"}, games[1].Moves())
+	assert.Equal(t, []string{"A comment\n\nwith blank lines"}, games[1].Root().Next().Comments)
+	assert.Equal(t, pgn.ResultWinWhite, games[1].Result())
+	assert.Empty(t, games[2].Headers())
+	assert.Equal(t, []string{longComment}, games[2].Root().Next().Comments)
+	assert.Empty(t, games[3].Moves())
+	assert.Equal(t, pgn.ResultWinWhite, games[3].Result())
+}
+
+func TestParse_Recovery(t *testing.T) {
+	data := `[Event "Good"]
+
+1. e4 e5 *
+
+[Event "Illegal character"]
+
+1. e4 <> e5 *
+2. d4 [Event "Not at the line start"] *
+
+[Event "No result"]
+
+1. d4 d5
+[Event "Unterminated comment"]
+
+1. c4 {c5 *
+`
+
+	type parsed struct {
+		event string
+		line  int
+	}
+
+	var got []parsed
+	for p, err := range pgn.Parse(strings.NewReader(data)) {
+		if err != nil {
+			var syntaxErr *pgn.SyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			require.ErrorIs(t, err, pgn.ErrDecode)
+			got = append(got, parsed{"", syntaxErr.Line})
+
+			continue
+		}
+
+		event, _ := p.Headers().Get("Event")
+		got = append(got, parsed{event.Value, 0})
+	}
+
+	assert.Equal(t, []parsed{{"Good", 0}, {"", 7}, {"", 13}, {"", 15}}, got)
+}
+
+func TestParse_SyntaxErrorPosition(t *testing.T) {
+	data := "[Event \"?\"]\n\n1. e4 e5\n2. Nf3 Nc6 3. Bb5 ) *"
+
+	for _, err := range pgn.Parse(strings.NewReader(data)) {
+		var syntaxErr *pgn.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+
+		assert.Equal(t, 4, syntaxErr.Line)
+		assert.Equal(t, int64(strings.Index(data, ")")), syntaxErr.Offset)
+		assert.Equal(t, "line 4, offset 40: error decoding PGN string: unexpected \")\"", err.Error())
+	}
+}
+
+func TestParse_ReaderError(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("1. e4 e5 * 1. d4"), failingReader{})
+
+	var errs []error
+	for _, err := range pgn.Parse(r) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 2)
+	require.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], errRead)
+}
+
+func TestParse_Writer(t *testing.T) {
+	games := []pgn.PGN{
+		pgn.NewPGN(pgn.Headers{pgn.NewHeader("Event", "First")}, []string{"e4", "e5"}, pgn.ResultInProcess),
+		pgn.NewPGN(pgn.Headers{}, []string{"d4"}, pgn.ResultWinWhite),
+		pgn.NewPGN(pgn.Headers{pgn.NewHeader("Event", "Third")}, []string{"c4"}, pgn.ResultDraw),
+	}
+
+	var sb strings.Builder
+	w := pgn.NewWriter(&sb)
+	for _, game := range games {
+		require.NoError(t, w.Write(game))
+	}
+
+	i := 0
+	for p, err := range pgn.Parse(strings.NewReader(sb.String())) {
+		require.NoError(t, err)
+		assert.Equal(t, games[i].Headers(), p.Headers())
+		assert.Equal(t, games[i].Moves(), p.Moves())
+		i++
+	}
+	assert.Equal(t, len(games), i)
+}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errRead
+}
+
 //nolint:decorder
 var expected = [...]string{
 	//nolint:lll
@@ -5,33 +5,43 @@ package pgn
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"regexp"
 	"strings"
-
-	"github.com/elaxer/rgx"
 )
 
+// DefaultMovesWidth is the maximum line length of the moves section used by PGN.String.
+const DefaultMovesWidth = 79
+
 var ErrDecode = errors.New("error decoding PGN string")
 
-var (
-	regexpSplit = regexp.MustCompile(`\s\s`)
-	regexpMove  = regexp.MustCompile(
-		`(([NBKRQ]?[a-h]?[1-8]?x?[a-h][1-8](?:=[NBRQ])?)|([0Oo]-[0Oo](-[0Oo])?))(\+|\#)?`,
-	)
-	regexpHeader = regexp.MustCompile(`\[(?P<name>[\w]+)\s+"(?P<value>[^"]*)"\]`)
-	regexpResult = regexp.MustCompile(`((1-0)|(0-1)|(1/2-1/2)|\*)\z`)
+var regexpMove = regexp.MustCompile(
+	`\A(([NBKRQ]?[a-h]?[1-8]?x?[a-h][1-8](?:=[NBRQ])?)|([0Oo]-[0Oo](-[0Oo])?))(\+|\#)?\z`,
 )
 
 // PGN represents a single chess game in PGN format.
-// It contains headers, moves, and the result of the game.
+// It contains headers, the game tree of the moves, and the result of the game.
 type PGN struct {
 	headers Headers
-	moves   []string
+	root    *Node
 	result  Result
 }
 
+// NewPGN creates the PGN of the game with the moves without annotations and variations.
 func NewPGN(headers Headers, moves []string, result Result) PGN {
-	return PGN{headers, moves, result}
+	root := NewNode()
+	node := root
+	for _, move := range moves {
+		node = node.AddMove(move)
+	}
+
+	return PGN{headers, root, result}
+}
+
+// NewPGNTree creates the PGN of the game with the game tree starting at the root node.
+func NewPGNTree(headers Headers, root *Node, result Result) PGN {
+	return PGN{headers, root, result}
 }
 
 // Headers returns the list of headers for the PGN game.
@@ -39,9 +49,24 @@ func (p PGN) Headers() Headers {
 	return p.headers
 }
 
-// Moves returns the list of moves in the PGN game.
+// Moves returns the list of the main line moves in the PGN game.
 func (p PGN) Moves() []string {
-	return p.moves
+	if p.root == nil {
+		return nil
+	}
+
+	mainline := p.root.Mainline()
+	moves := make([]string, 0, len(mainline))
+	for _, node := range mainline {
+		moves = append(moves, node.Move)
+	}
+
+	return moves
+}
+
+// Root returns the root node of the game tree with the comments, NAGs and variations of the moves.
+func (p PGN) Root() *Node {
+	return p.root
 }
 
 // Result returns the result of the PGN game.
@@ -51,81 +76,53 @@ func (p PGN) Result() Result {
 
 // Format returns the PGN as a formatted string,
 // wrapping move text at the specified width.
-// movesWidth specifies the maximum line length for the moves section.
-// Headers are included at the top, followed by the moves, then the result.
+// movesWidth specifies the maximum line length for the moves section,
+// it's limited by MaxLineLength; the moves aren't wrapped if it isn't positive.
+// Headers are included at the top, followed by the moves with their comments, NAGs and variations, then the result.
+// The move numbers start from the FEN header position if it's given.
 func (p PGN) Format(movesWidth int) string {
 	var pgnStr strings.Builder
 	pgnStr.WriteString(encodeHeaders(p.headers) + "\n\n")
 
-	movesStr := wrapText(encodeMoves(p.moves), movesWidth)
+	root := p.root
+	if root == nil {
+		root = NewNode()
+	}
+
+	movesStr := wrapText(encodeMovetext(root, startingPly(p.headers), p.result), min(movesWidth, MaxLineLength))
 	pgnStr.WriteString(movesStr)
 
-	return strings.TrimSpace(pgnStr.String() + " " + string(p.result))
+	return strings.TrimSpace(pgnStr.String())
 }
 
 func (p PGN) String() string {
-	return p.Format(79)
+	return p.Format(DefaultMovesWidth)
 }
 
 // FromString parses a single PGN game from the provided string.
 // pgnStr should contain headers, moves and result.
 // Headers can be omitted.
+// The comments, NAGs and variations of the moves are kept in the game tree.
 // Returns a PGN object containing headers, moves, and the result.
-// Returns ErrDecode if the string does not match the expected PGN format.
+// Returns ErrDecode if the string does not match the expected PGN format,
+// it's wrapped by *SyntaxError with the position of the problem if there is one.
 func FromString(pgnStr string) (PGN, error) {
-	pgnStr = strings.TrimSpace(strings.ReplaceAll(pgnStr, "\r\n", "\n"))
-
-	s := regexpSplit.Split(strings.TrimSpace(pgnStr), 2)
-	headerStr := ""
-	movesStr := ""
-	if len(s) == 1 {
-		movesStr = s[0]
-	} else {
-		headerStr = s[0]
-		movesStr = s[1]
-	}
+	p := newParser(strings.NewReader(pgnStr))
 
-	moves, err := decodeMoves(movesStr)
-	if err != nil {
-		return PGN{}, err
+	game, err := p.parseGame()
+	if errors.Is(err, io.EOF) {
+		return PGN{}, fmt.Errorf("%w: no game", ErrDecode)
 	}
-	result, err := decodeResult(movesStr)
 	if err != nil {
 		return PGN{}, err
 	}
-
-	return PGN{decodeHeaders(headerStr), moves, result}, nil
-}
-
-func decodeHeaders(pgnStr string) Headers {
-	headers := make(Headers, 0)
-
-	data, err := rgx.Groups(regexpHeader, pgnStr)
-	if err != nil {
-		return headers
+	if len(game.root.children) == 0 {
+		return PGN{}, fmt.Errorf("%w: no moves", ErrDecode)
 	}
 
-	for _, match := range data {
-		headers = append(headers, NewHeader(match["name"], match["value"]))
-	}
-
-	return headers
-}
-
-func decodeMoves(pgnStr string) ([]string, error) {
-	data := regexpMove.FindAllString(pgnStr, -1)
-	if len(data) == 0 {
-		return nil, ErrDecode
-	}
-
-	return data, nil
-}
-
-func decodeResult(pgnStr string) (Result, error) {
-	result := Result(regexpResult.FindString(pgnStr))
-	if result == "" {
-		return "", ErrDecode
+	if tok, err := p.next(); err != nil || tok.kind != tokenEOF {
+		return PGN{}, newSyntaxError(tok, "unexpected data after the game")
 	}
 
-	return result, nil
+	return game, nil
 }
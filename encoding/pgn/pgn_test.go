@@ -1,8 +1,10 @@
 package pgn_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/elaxer/standardchess"
 	"github.com/elaxer/standardchess/encoding/pgn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -672,3 +674,99 @@ h1=Q 47. Re8+ Kxe8 48. c8=Q+ Ke7 49. Nc6+ Qxc6+ 50. Qxc6 Rd6 *`
 
 	assert.Equal(t, pgnStr, pgn.String())
 }
+
+func TestPGN_Format_Annotations(t *testing.T) {
+	const pgnStr = `[Event "Annotated"]
+
+{Opening comment} 1. e4 $1 $14 {Best by test} (1. d4 d5 (1... Nf6 2. c4) 2. c4)
+(1. c4) 1... e5 $6 {The main line} 2. Nf3 ({Instead} 2. f4 exf4 {Gambit}) 2...
+Nc6 (2... Nf6 $2) 3. Bb5 *`
+
+	p, err := pgn.FromString(pgnStr)
+	require.NoError(t, err)
+
+	assert.Equal(t, pgnStr, p.String())
+}
+
+func TestPGN_Format_Tree(t *testing.T) {
+	root := pgn.NewNode()
+	e4 := root.AddMove("e4")
+	e4.Comments = []string{"King's pawn"}
+	e5 := e4.AddMove("e5")
+	e5.NAGs = []int{pgn.NAGGoodMove}
+	e4.AddMove("c5").StartingComments = []string{"Sicilian"}
+	e5.AddMove("Nf3")
+
+	p := pgn.NewPGNTree(pgn.Headers{}, root, pgn.ResultInProcess)
+
+	assert.Equal(t, []string{"e4", "e5", "Nf3"}, p.Moves())
+	assert.Equal(t, "1. e4 {King's pawn} 1... e5 $1 ({Sicilian} 1... c5) 2. Nf3 *", p.Format(0))
+}
+
+func TestPGN_Format_FENMoveNumber(t *testing.T) {
+	p := pgn.NewPGN(pgn.Headers{
+		pgn.NewHeader(pgn.HeaderSetUp, "1"),
+		pgn.NewHeader(pgn.HeaderFEN, "4k3/8/8/8/8/8/8/R3K3 b Q - 3 20"),
+	}, []string{"Kd7", "O-O-O+"}, pgn.ResultInProcess)
+
+	assert.True(t, strings.HasSuffix(p.Format(0), "\n\n20... Kd7 21. O-O-O+ *"))
+}
+
+func TestPGN_Format_MaxLineLength(t *testing.T) {
+	moves := make([]string, 0, 200)
+	for range 50 {
+		moves = append(moves, "Nf3", "Nf6", "Ng1", "Ng8")
+	}
+	p := pgn.NewPGN(pgn.Headers{}, moves, pgn.ResultDraw)
+
+	lines := strings.Split(p.Format(1000), "\n")
+	require.Greater(t, len(lines), 1)
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), pgn.MaxLineLength)
+	}
+
+	assert.Equal(t, strings.Fields(p.Format(0)), strings.Fields(p.Format(1000)))
+}
+
+func TestEncode_WithOpening(t *testing.T) {
+	tests := []struct {
+		name    string
+		moves   []string
+		headers pgn.Headers
+		want    pgn.Headers
+	}{
+		{
+			"variation",
+			[]string{"e4", "e5", "Nf3", "Nc6", "Bb5", "Nf6", "O-O"},
+			pgn.Headers{pgn.NewHeader(pgn.HeaderEvent, "?")},
+			pgn.Headers{
+				pgn.NewHeader(pgn.HeaderEvent, "?"),
+				pgn.NewHeader(pgn.HeaderECO, "C65"),
+				pgn.NewHeader(pgn.HeaderOpening, "Ruy Lopez"),
+				pgn.NewHeader(pgn.HeaderVariation, "Berlin Defense"),
+			},
+		},
+		{
+			"no_variation",
+			[]string{"e4", "c5"},
+			nil,
+			pgn.Headers{pgn.NewHeader(pgn.HeaderECO, "B20"), pgn.NewHeader(pgn.HeaderOpening, "Sicilian Defense")},
+		},
+		{
+			"given_headers",
+			[]string{"e4", "c5"},
+			pgn.Headers{pgn.NewHeader(pgn.HeaderECO, "B21")},
+			pgn.Headers{pgn.NewHeader(pgn.HeaderECO, "B21"), pgn.NewHeader(pgn.HeaderOpening, "Sicilian Defense")},
+		},
+		{"unknown", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := standardchess.NewBoardFromMoves(tt.moves)
+			require.NoError(t, err)
+
+			p := pgn.Encode(tt.headers, board, pgn.ResultInProcess, pgn.WithOpening())
+			assert.Equal(t, tt.want, p.Headers())
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package pgn
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+)
+
+var (
+	ErrIllegalMove    = errors.New("illegal move")
+	ErrResultMismatch = errors.New("result mismatch")
+)
+
+// Replay plays the main line moves of the game on the board the game starts from, see NewBoard.
+// Only checkmate and stalemate end the game, the moves are played in the positions drawn by the rules too,
+// e.g. by a threefold repetition nobody has claimed, see standardchess.MakeMovePastDraw.
+// ErrIllegalMove is returned with the ply, the move and the reason if a move can't be made,
+// the board is returned then in the position before that move.
+func Replay(p PGN) (chess.Board, error) {
+	board, err := NewBoard(p.headers)
+	if err != nil {
+		return nil, err
+	}
+
+	startPly := (board.Setup().MoveNumber - 1) * 2
+	if board.Turn().IsBlack() {
+		startPly++
+	}
+
+	for i, move := range p.Moves() {
+		if _, err := standardchess.MakeMovePastDraw(board, move); err != nil {
+			return board, fmt.Errorf("%w: ply %d, %s: %w", ErrIllegalMove, i+1, numberedMove(startPly+i, move), err)
+		}
+	}
+
+	return board, nil
+}
+
+// Validate checks that the main line moves of the game are legal and the result of the game
+// agrees with the Result header, the final checkmate or stalemate and the Termination header.
+// ErrIllegalMove or ErrResultMismatch is returned if it doesn't.
+func Validate(p PGN) error {
+	board, err := Replay(p)
+	if err != nil {
+		return err
+	}
+
+	if header, ok := p.headers.Get(HeaderResult); ok && Result(header.Value) != p.result {
+		return fmt.Errorf(
+			"%w: the %s header %q differs from the game termination marker %q",
+			ErrResultMismatch, HeaderResult, header.Value, p.result,
+		)
+	}
+
+	if state := board.State(); (state == standardchess.StateCheckmate || state == standardchess.StateStalemate) &&
+		ResultFromBoard(board) != p.result {
+		return fmt.Errorf(
+			"%w: the game is ended by %s, so the result is %q, not %q",
+			ErrResultMismatch, state, ResultFromBoard(board), p.result,
+		)
+	}
+
+	termination, ok := p.headers.Get(HeaderTermination)
+	if isUnterminated := strings.EqualFold(termination.Value, TerminationUnterminated); ok &&
+		isUnterminated != p.result.IsInProcess() {
+		return fmt.Errorf(
+			"%w: the %s header %q doesn't agree with the result %q",
+			ErrResultMismatch, HeaderTermination, termination.Value, p.result,
+		)
+	}
+
+	return nil
+}
+
+// numberedMove returns the move with its number, e.g. "3. Bc4" or "3... Nf6".
+func numberedMove(ply int, move string) string {
+	if ply%2 == 0 {
+		return strconv.Itoa(ply/2+1) + ". " + move
+	}
+
+	return strconv.Itoa(ply/2+1) + "... " + move
+}
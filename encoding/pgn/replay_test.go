@@ -0,0 +1,142 @@
+package pgn_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	tests := []struct {
+		name    string
+		pgn     string
+		wantFEN string
+	}{
+		{
+			"standard",
+			"1. e4 e5 2. Nf3 (2. f4) 2... Nc6 3. Bb5 *",
+			"r1bqkbnr/pppp1ppp/2n5/1B2p3/4P3/5N2/PPPP1PPP/RNBQK2R b KQkq - 3 3",
+		},
+		{
+			"fen_header",
+			`[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/8/R3K3 b Q - 3 20"]
+
+20... Kd7 21. O-O-O+ *`,
+			"8/3k4/8/8/8/8/8/2KR4 b - - 5 21",
+		},
+		{
+			"unclaimed_repetition",
+			"1. Nf3 Nf6 2. Ng1 Ng8 3. Nf3 Nf6 4. Ng1 Ng8 5. e4 e5 1-0",
+			"rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 6",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := pgn.FromString(tt.pgn)
+			require.NoError(t, err)
+
+			board, err := pgn.Replay(p)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantFEN, fen.Encode(board).String())
+		})
+	}
+}
+
+func TestReplay_IllegalMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		pgn     string
+		wantErr string
+		wantPly int
+	}{
+		{"white", "1. e4 e5 2. Ke3 *", "ply 3, 2. Ke3", 2},
+		{"black", "1. e4 e5 2. Nf3 Ke6 *", "ply 4, 2... Ke6", 3},
+		{
+			"fen_header",
+			`[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/8/R3K3 b Q - 3 20"]
+
+20... Kd7 21. O-O *`,
+			"ply 2, 21. O-O",
+			1,
+		},
+		{"after_checkmate", "1. f3 e5 2. g4 Qh4# 3. a3 0-1", "ply 5, 3. a3", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := pgn.FromString(tt.pgn)
+			require.NoError(t, err)
+
+			board, err := pgn.Replay(p)
+			require.ErrorIs(t, err, pgn.ErrIllegalMove)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Len(t, board.MoveHistory(), tt.wantPly)
+
+			assert.ErrorIs(t, pgn.Validate(p), pgn.ErrIllegalMove)
+		})
+	}
+}
+
+func TestReplay_CheckmateError(t *testing.T) {
+	p, err := pgn.FromString("1. f3 e5 2. g4 Qh4# 3. a3 0-1")
+	require.NoError(t, err)
+
+	_, err = pgn.Replay(p)
+	assert.ErrorIs(t, err, standardchess.ErrCannotMoveInTerminalState)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pgn     string
+		wantErr error
+	}{
+		{"checkmate", `[Result "0-1"]
+
+1. f3 e5 2. g4 Qh4# 0-1`, nil},
+		{"resignation", "1. e4 e5 2. Qh5 Nc6 1-0", nil},
+		{"in_process", `[Termination "unterminated"]
+
+1. e4 *`, nil},
+		{"normal_termination", `[Termination "normal"]
+
+1. e4 e5 1/2-1/2`, nil},
+		{"result_header", `[Result "1-0"]
+
+1. f3 e5 2. g4 Qh4# 0-1`, pgn.ErrResultMismatch},
+		{"checkmate_draw", "1. f3 e5 2. g4 Qh4# 1/2-1/2", pgn.ErrResultMismatch},
+		{"checkmate_in_process", "1. f3 e5 2. g4 Qh4# *", pgn.ErrResultMismatch},
+		{"stalemate_win", `[SetUp "1"]
+[FEN "7k/5Q2/8/6K1/8/8/8/8 w - - 0 1"]
+
+1. Kg6 1-0`, pgn.ErrResultMismatch},
+		{"unterminated_result", `[Termination "unterminated"]
+
+1. e4 e5 1-0`, pgn.ErrResultMismatch},
+		{"terminated_in_process", `[Termination "time forfeit"]
+
+1. e4 e5 *`, pgn.ErrResultMismatch},
+		{"unclaimed_repetition", "1. Nf3 Nf6 2. Ng1 Ng8 3. Nf3 Nf6 4. Ng1 Ng8 1-0", nil},
+		{"illegal_move", "1. e4 e5 2. Nf4 *", pgn.ErrIllegalMove},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := pgn.FromString(tt.pgn)
+			require.NoError(t, err)
+
+			err = pgn.Validate(p)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -52,3 +52,7 @@ func (r Result) IsWinBlack() bool {
 func (r Result) IsDraw() bool {
 	return r == ResultDraw
 }
+
+func (r Result) isTerminationMarker() bool {
+	return r == ResultInProcess || r == ResultWinWhite || r == ResultWinBlack || r == ResultDraw
+}
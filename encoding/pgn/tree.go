@@ -0,0 +1,85 @@
+package pgn
+
+// The Numeric Annotation Glyphs of the move suffix annotations.
+const (
+	NAGGoodMove        = 1
+	NAGMistake         = 2
+	NAGBrilliantMove   = 3
+	NAGBlunder         = 4
+	NAGSpeculativeMove = 5
+	NAGDubiousMove     = 6
+)
+
+var suffixAnnotations = map[string]int{
+	"!":  NAGGoodMove,
+	"?":  NAGMistake,
+	"!!": NAGBrilliantMove,
+	"??": NAGBlunder,
+	"!?": NAGSpeculativeMove,
+	"?!": NAGDubiousMove,
+}
+
+// Node is a node of the game tree: a move with its annotations and the moves played after it.
+// The root node of a game has no move, its comments are given before the first move of the game.
+type Node struct {
+	// Move is the move in SAN, empty for the root node.
+	Move string
+	// NAGs are the Numeric Annotation Glyphs of the move, e.g. NAGGoodMove for "!".
+	NAGs []int
+	// Comments are the comments given after the move.
+	Comments []string
+	// StartingComments are the comments given before the first move of a variation.
+	StartingComments []string
+
+	parent   *Node
+	children []*Node
+}
+
+// NewNode creates the root node of a game tree.
+func NewNode() *Node {
+	return &Node{}
+}
+
+// AddMove adds the move played after the move of the node and returns its node.
+// The first added move continues the main line, the next ones start variations.
+func (n *Node) AddMove(move string) *Node {
+	child := &Node{Move: move, parent: n}
+	n.children = append(n.children, child)
+
+	return child
+}
+
+// Parent returns the node of the previous move, nil for the root node.
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Children returns the nodes of the moves played after the move of the node.
+// The first one continues the main line, the others are variations.
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// Next returns the node of the main line move played after the move of the node, nil if there is no such move.
+func (n *Node) Next() *Node {
+	if len(n.children) == 0 {
+		return nil
+	}
+
+	return n.children[0]
+}
+
+// Mainline returns the nodes of the main line moves played after the move of the node.
+func (n *Node) Mainline() []*Node {
+	nodes := make([]*Node, 0)
+	for node := n.Next(); node != nil; node = node.Next() {
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// IsRoot reports whether the node is the root of the game tree.
+func (n *Node) IsRoot() bool {
+	return n.parent == nil
+}
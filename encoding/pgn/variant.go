@@ -0,0 +1,95 @@
+package pgn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/internal/setup"
+)
+
+const (
+	// HeaderVariant is the name of the header with the variant of the game.
+	HeaderVariant = "Variant"
+	// HeaderSetUp is the name of the header which is "1" if the game starts from the FEN header position.
+	HeaderSetUp = "SetUp"
+	// HeaderFEN is the name of the header with the initial position of the game.
+	HeaderFEN = "FEN"
+
+	// VariantChess960 is the value of the Variant header of Chess960 games.
+	VariantChess960 = "Chess960"
+)
+
+var ErrUnsupportedVariant = errors.New("unsupported variant")
+
+var standardInitialFEN = fen.Encode(standardchess.NewBoard()).String()
+
+// NewBoard creates the board the game starts from according to the Variant, SetUp and FEN headers.
+// The standard board is created if there are no such headers.
+// A Chess960 game must have the FEN header.
+func NewBoard(headers Headers) (standardchess.Board, error) {
+	isChess960, err := isChess960Variant(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	setUp, _ := headers.Get(HeaderSetUp)
+	fenHeader, hasFEN := headers.Get(HeaderFEN)
+
+	switch {
+	case hasFEN && setUp.Value != "0":
+		if isChess960 {
+			return fen.DecodeChess960(fenHeader.Value)
+		}
+
+		return fen.Decode(fenHeader.Value)
+	case setUp.Value == "1":
+		return nil, fmt.Errorf("%w: the %s header is missing", ErrDecode, HeaderFEN)
+	case isChess960:
+		return nil, fmt.Errorf("%w: the initial position of the %s game is unknown", ErrDecode, VariantChess960)
+	default:
+		return standardchess.NewBoard(), nil
+	}
+}
+
+// setUpHeaders returns the Variant, SetUp and FEN headers describing the initial position of the board
+// which are missing in the headers.
+// The SetUp and FEN headers are returned only if the game isn't started from the standard initial position.
+func setUpHeaders(headers Headers, board chess.Board) Headers {
+	setUpHeaders := make(Headers, 0, 3)
+
+	boardSetup := setup.FromBoard(board)
+	if _, ok := headers.Get(HeaderVariant); !ok && boardSetup.Chess960 {
+		setUpHeaders = append(setUpHeaders, NewHeader(HeaderVariant, VariantChess960))
+	}
+
+	if _, ok := headers.Get(HeaderFEN); ok {
+		return setUpHeaders
+	}
+
+	initialFEN, err := fen.EncodeInitial(board)
+	if err != nil || (!boardSetup.Chess960 && initialFEN.String() == standardInitialFEN) {
+		return setUpHeaders
+	}
+
+	return append(setUpHeaders, NewHeader(HeaderSetUp, "1"), NewHeader(HeaderFEN, initialFEN.String()))
+}
+
+func isChess960Variant(headers Headers) (bool, error) {
+	variant, ok := headers.Get(HeaderVariant)
+	if !ok {
+		return false, nil
+	}
+
+	switch strings.NewReplacer(" ", "", "-", "").Replace(strings.ToLower(variant.Value)) {
+	case "", "standard", "chess", "normal":
+		return false, nil
+	case "chess960", "fischerandom", "fischerrandom", "960":
+		return true, nil
+	default:
+		return false, fmt.Errorf("%w: %s", ErrUnsupportedVariant, variant.Value)
+	}
+}
@@ -0,0 +1,129 @@
+package pgn_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBoard(t *testing.T) {
+	tests := []struct {
+		name         string
+		headers      pgn.Headers
+		wantFEN      string
+		wantChess960 bool
+		wantErr      error
+	}{
+		{
+			"standard",
+			pgn.Headers{pgn.NewHeader("Event", "?")},
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			false,
+			nil,
+		},
+		{
+			"set_up",
+			pgn.Headers{
+				pgn.NewHeader(pgn.HeaderSetUp, "1"),
+				pgn.NewHeader(pgn.HeaderFEN, "4k3/8/8/8/8/8/8/4K2R b K - 0 40"),
+			},
+			"4k3/8/8/8/8/8/8/4K2R b K - 0 40",
+			false,
+			nil,
+		},
+		{
+			"chess960",
+			pgn.Headers{
+				pgn.NewHeader(pgn.HeaderVariant, "Chess960"),
+				pgn.NewHeader(pgn.HeaderSetUp, "1"),
+				pgn.NewHeader(pgn.HeaderFEN, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"),
+			},
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			true,
+			nil,
+		},
+		{
+			"fischerandom_shredder_fen",
+			pgn.Headers{
+				pgn.NewHeader(pgn.HeaderVariant, "Fischerandom"),
+				pgn.NewHeader(pgn.HeaderSetUp, "1"),
+				pgn.NewHeader(pgn.HeaderFEN, "bbqnnrkr/pppppppp/8/8/8/8/PPPPPPPP/BBQNNRKR w HFhf - 0 1"),
+			},
+			"bbqnnrkr/pppppppp/8/8/8/8/PPPPPPPP/BBQNNRKR w KQkq - 0 1",
+			true,
+			nil,
+		},
+		{
+			"missing_fen",
+			pgn.Headers{pgn.NewHeader(pgn.HeaderSetUp, "1")},
+			"",
+			false,
+			pgn.ErrDecode,
+		},
+		{
+			"chess960_without_fen",
+			pgn.Headers{pgn.NewHeader(pgn.HeaderVariant, "Chess960")},
+			"",
+			false,
+			pgn.ErrDecode,
+		},
+		{
+			"unsupported_variant",
+			pgn.Headers{pgn.NewHeader(pgn.HeaderVariant, "Crazyhouse")},
+			"",
+			false,
+			pgn.ErrUnsupportedVariant,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := pgn.NewBoard(tt.headers)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFEN, fen.Encode(board).String())
+			assert.Equal(t, tt.wantChess960, board.Setup().Chess960)
+		})
+	}
+}
+
+func TestEncode_SetUpHeaders(t *testing.T) {
+	t.Run("standard", func(t *testing.T) {
+		board, err := standardchess.NewBoardFromMoves([]string{"e4", "e5"})
+		require.NoError(t, err)
+
+		headers := pgn.Encode(pgn.Headers{pgn.NewHeader("Event", "?")}, board, pgn.ResultDraw).Headers()
+		assert.Equal(t, pgn.Headers{pgn.NewHeader("Event", "?")}, headers)
+	})
+
+	t.Run("chess960", func(t *testing.T) {
+		board, err := standardchess.NewBoard960(0)
+		require.NoError(t, err)
+		_, err = board.MakeMove("e1f3")
+		require.NoError(t, err)
+
+		headers := pgn.Encode(pgn.Headers{pgn.NewHeader("Event", "?")}, board, pgn.ResultDraw).Headers()
+		assert.Equal(t, pgn.Headers{
+			pgn.NewHeader("Event", "?"),
+			pgn.NewHeader(pgn.HeaderVariant, pgn.VariantChess960),
+			pgn.NewHeader(pgn.HeaderSetUp, "1"),
+			pgn.NewHeader(pgn.HeaderFEN, "bbqnnrkr/pppppppp/8/8/8/8/PPPPPPPP/BBQNNRKR w KQkq - 0 1"),
+		}, headers)
+	})
+
+	t.Run("given_headers", func(t *testing.T) {
+		board, err := fen.Decode("4k3/8/8/8/8/8/8/4K2R b K - 0 40")
+		require.NoError(t, err)
+
+		headers := pgn.Headers{pgn.NewHeader(pgn.HeaderFEN, "4k3/8/8/8/8/8/8/4K2R b K - 0 40")}
+		assert.Equal(t, headers, pgn.Encode(headers, board, pgn.ResultDraw).Headers())
+	})
+}
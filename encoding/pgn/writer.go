@@ -0,0 +1,62 @@
+package pgn
+
+import (
+	"io"
+	"slices"
+)
+
+// Writer writes PGN games to an io.Writer one at a time, separating them by a blank line.
+// Each game is written by a single call to the underlying writer,
+// wrap it with a bufio.Writer to reduce the number of the calls.
+type Writer struct {
+	// MovesWidth is the maximum line length of the moves section, see PGN.Format.
+	MovesWidth int
+	// SevenTagRoster makes the writer write the Seven Tag Roster headers first and in their order.
+	// The missing ones are added with the unknown values, the Result header is set to the result of the game.
+	SevenTagRoster bool
+
+	w       io.Writer
+	written bool
+}
+
+// NewWriter creates a writer writing the games to w with DefaultMovesWidth.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{MovesWidth: DefaultMovesWidth, w: w}
+}
+
+// Write writes the game.
+func (w *Writer) Write(p PGN) error {
+	if w.SevenTagRoster {
+		p.headers = sevenTagRosterHeaders(p.headers, p.result)
+	}
+
+	str := p.Format(w.MovesWidth) + "\n"
+	if w.written {
+		str = "\n" + str
+	}
+
+	if _, err := io.WriteString(w.w, str); err != nil {
+		return err
+	}
+	w.written = true
+
+	return nil
+}
+
+// sevenTagRosterHeaders returns the Seven Tag Roster headers in their order followed by the other headers.
+// The missing Seven Tag Roster headers get the unknown values and the Result header is set to the result.
+func sevenTagRosterHeaders(headers Headers, result Result) Headers {
+	headers = slices.Clone(headers)
+	for _, name := range sevenTagRoster {
+		switch _, ok := headers.Get(name); {
+		case ok:
+		case name == HeaderDate:
+			headers.SetDate(Date{})
+		default:
+			headers.Set(name, "?")
+		}
+	}
+	headers.SetResult(result)
+
+	return headers.Sorted()
+}
@@ -0,0 +1,90 @@
+package pgn_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errWrite = errors.New("write error")
+
+type failingWriter struct{}
+
+func TestWriter_Write(t *testing.T) {
+	games := []pgn.PGN{
+		pgn.NewPGN(pgn.Headers{pgn.NewHeader("Event", "First")}, []string{"e4", "e5"}, pgn.ResultInProcess),
+		pgn.NewPGN(pgn.Headers{}, []string{"d4"}, pgn.ResultWinWhite),
+		pgn.NewPGN(pgn.Headers{pgn.NewHeader("Event", "Third")}, []string{"c4"}, pgn.ResultDraw),
+	}
+
+	var sb strings.Builder
+	w := pgn.NewWriter(&sb)
+	for _, game := range games {
+		require.NoError(t, w.Write(game))
+	}
+
+	assert.Equal(t, `[Event "First"]
+
+1. e4 e5 *
+
+1. d4 1-0
+
+[Event "Third"]
+
+1. c4 1/2-1/2
+`, sb.String())
+}
+
+func TestWriter_MovesWidth(t *testing.T) {
+	p := pgn.NewPGN(pgn.Headers{}, []string{"e4", "e5", "Nf3", "Nc6", "Bb5"}, pgn.ResultInProcess)
+
+	var sb strings.Builder
+	w := pgn.NewWriter(&sb)
+	w.MovesWidth = 12
+	require.NoError(t, w.Write(p))
+
+	assert.Equal(t, "1. e4 e5 2.\nNf3 Nc6 3.\nBb5 *\n", sb.String())
+}
+
+func TestWriter_SevenTagRoster(t *testing.T) {
+	p := pgn.NewPGN(pgn.Headers{
+		pgn.NewHeader("ECO", "C60"),
+		pgn.NewHeader("White", "Kasparov"),
+		pgn.NewHeader("Result", "1-0"),
+		pgn.NewHeader("Event", "Match"),
+		pgn.NewHeader("Black", "Karpov"),
+	}, []string{"e4"}, pgn.ResultInProcess)
+
+	var sb strings.Builder
+	w := pgn.NewWriter(&sb)
+	w.SevenTagRoster = true
+	require.NoError(t, w.Write(p))
+
+	assert.Equal(t, `[Event "Match"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "?"]
+[White "Kasparov"]
+[Black "Karpov"]
+[Result "*"]
+[ECO "C60"]
+
+1. e4 *
+`, sb.String())
+	assert.Len(t, p.Headers(), 5)
+}
+
+func TestWriter_Error(t *testing.T) {
+	w := pgn.NewWriter(failingWriter{})
+
+	err := w.Write(pgn.NewPGN(pgn.Headers{}, []string{"e4"}, pgn.ResultInProcess))
+	assert.ErrorIs(t, err, errWrite)
+}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
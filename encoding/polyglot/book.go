@@ -0,0 +1,269 @@
+package polyglot
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+)
+
+// EntrySize is the size of a book entry in bytes.
+const EntrySize = 16
+
+var (
+	ErrInvalidBook      = errors.New("invalid polyglot book")
+	ErrUnsupportedBoard = errors.New("polyglot books support 8x8 boards only")
+)
+
+// promotionNotations are the notations of the promoted pieces by their Polyglot codes.
+var promotionNotations = [...]string{
+	1: standardchess.NotationKnight,
+	2: standardchess.NotationBishop,
+	3: standardchess.NotationRook,
+	4: standardchess.NotationQueen,
+}
+
+// Entry is an entry of a book: a move of the position with the key.
+type Entry struct {
+	// Key is the Polyglot Zobrist key of the position.
+	Key uint64
+	// Move is the move encoded by Polyglot, castling is encoded as the king moving to its rook, e.g. e1h1.
+	Move uint16
+	// Weight is the weight of the move, the better moves have the greater weights.
+	Weight uint16
+	// Learn is the learning data of the move.
+	Learn uint32
+}
+
+// BookMove is a legal move of a book with its weight.
+type BookMove struct {
+	Move   standardchess.Move
+	Weight int
+}
+
+// Book is an opening book in the Polyglot format: the entries sorted by the keys.
+// The entries are read on demand, so the book isn't loaded into memory.
+type Book struct {
+	r     io.ReaderAt
+	count int
+	close func() error
+}
+
+// Open opens the book file.
+// The book must be closed after use.
+func Open(path string) (*Book, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	book, err := NewBook(f, info.Size())
+	if err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+	book.close = f.Close
+
+	return book, nil
+}
+
+// NewBook creates the book reading the entries from r of the size in bytes.
+// ErrInvalidBook is returned if the size isn't a multiple of EntrySize.
+func NewBook(r io.ReaderAt, size int64) (*Book, error) {
+	if size%EntrySize != 0 {
+		return nil, fmt.Errorf("%w: the size %d isn't a multiple of %d", ErrInvalidBook, size, EntrySize)
+	}
+
+	return &Book{r: r, count: int(size / EntrySize)}, nil
+}
+
+// Close closes the book file if the book is opened by Open.
+func (b *Book) Close() error {
+	if b.close == nil {
+		return nil
+	}
+
+	return b.close()
+}
+
+// Entries returns the entries of the position with the key in the order of the book.
+func (b *Book) Entries(key uint64) ([]Entry, error) {
+	var err error
+	i := sort.Search(b.count, func(i int) bool {
+		entry, readErr := b.entry(i)
+		if readErr != nil {
+			err = readErr
+
+			return true
+		}
+
+		return entry.Key >= key
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0)
+	for ; i < b.count; i++ {
+		entry, err := b.entry(i)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Key != key {
+			break
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Moves returns the legal book moves of the position on the board sorted by their weights in descending order.
+// The entries which don't describe legal moves are skipped.
+func (b *Book) Moves(board chess.Board) ([]BookMove, error) {
+	if board.Squares().EdgePosition() != standardchess.EdgePosition {
+		return nil, ErrUnsupportedBoard
+	}
+
+	entries, err := b.Entries(Key(board))
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+
+	legalMoves := standardchess.MoveList(board)
+	moves := make([]BookMove, 0, len(entries))
+	for _, entry := range entries {
+		if move, ok := findMove(legalMoves, entry.Move); ok {
+			moves = append(moves, BookMove{move, int(entry.Weight)})
+		}
+	}
+
+	slices.SortStableFunc(moves, func(a, b BookMove) int {
+		return b.Weight - a.Weight
+	})
+
+	return moves, nil
+}
+
+// BestMove returns the book move with the greatest weight.
+// The second value is false if there are no book moves for the position.
+func (b *Book) BestMove(board chess.Board) (standardchess.Move, bool, error) {
+	moves, err := b.Moves(board)
+	if err != nil || len(moves) == 0 {
+		return standardchess.Move{}, false, err
+	}
+
+	return moves[0].Move, true, nil
+}
+
+// RandomMove returns a random book move chosen with the probability proportional to its weight,
+// the moves are chosen uniformly if all the weights are zero.
+// The global random generator is used if r is nil.
+// The second value is false if there are no book moves for the position.
+func (b *Book) RandomMove(board chess.Board, r *rand.Rand) (standardchess.Move, bool, error) {
+	moves, err := b.Moves(board)
+	if err != nil || len(moves) == 0 {
+		return standardchess.Move{}, false, err
+	}
+
+	intN := rand.IntN
+	if r != nil {
+		intN = r.IntN
+	}
+
+	total := 0
+	for _, move := range moves {
+		total += move.Weight
+	}
+	if total == 0 {
+		return moves[intN(len(moves))].Move, true, nil
+	}
+
+	n := intN(total)
+	for _, move := range moves {
+		if n < move.Weight {
+			return move.Move, true, nil
+		}
+		n -= move.Weight
+	}
+
+	return moves[len(moves)-1].Move, true, nil
+}
+
+func (b *Book) entry(i int) (Entry, error) {
+	var buf [EntrySize]byte
+	if _, err := b.r.ReadAt(buf[:], int64(i)*EntrySize); err != nil {
+		return Entry{}, err
+	}
+
+	return Entry{
+		Key:    binary.BigEndian.Uint64(buf[0:8]),
+		Move:   binary.BigEndian.Uint16(buf[8:10]),
+		Weight: binary.BigEndian.Uint16(buf[10:12]),
+		Learn:  binary.BigEndian.Uint32(buf[12:16]),
+	}, nil
+}
+
+// UCI returns the move of the entry in the UCI notation as it's encoded by Polyglot,
+// e.g. "g1f3", "e7e8q" or "e1h1" for castling.
+func (e Entry) UCI() string {
+	from, to, promotion := decodeMove(e.Move)
+
+	return from.String() + to.String() + strings.ToLower(promotion)
+}
+
+// findMove returns the legal move encoded by Polyglot.
+func findMove(legalMoves []standardchess.Move, encoded uint16) (standardchess.Move, bool) {
+	from, to, promotion := decodeMove(encoded)
+	i := slices.IndexFunc(legalMoves, func(move standardchess.Move) bool {
+		if move.From != from {
+			return false
+		}
+		if move.IsCastling {
+			return isCastlingTo(move, to)
+		}
+
+		return move.To == to && move.PromotedPieceNotation == promotion
+	})
+	if i < 0 {
+		return standardchess.Move{}, false
+	}
+
+	return legalMoves[i], true
+}
+
+// isCastlingTo reports whether the castling is encoded as the king moving to the square of its rook.
+func isCastlingTo(move standardchess.Move, rookPosition chess.Position) bool {
+	if rookPosition.Rank != move.From.Rank {
+		return false
+	}
+
+	return (rookPosition.File > move.From.File) == move.CastlingType.IsShort()
+}
+
+func decodeMove(move uint16) (from, to chess.Position, promotion string) {
+	to = chess.NewPosition(chess.File(move&7+1), chess.Rank(move>>3&7+1))
+	from = chess.NewPosition(chess.File(move>>6&7+1), chess.Rank(move>>9&7+1))
+	if code := move >> 12 & 7; int(code) < len(promotionNotations) {
+		promotion = promotionNotations[code]
+	}
+
+	return from, to, promotion
+}
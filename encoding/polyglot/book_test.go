@@ -0,0 +1,271 @@
+package polyglot_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/encoding/polyglot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBook_Moves(t *testing.T) {
+	tests := []struct {
+		name    string
+		moves   []string
+		entries []polyglot.Entry
+		want    []string
+		wantW   []int
+	}{
+		{
+			"initial",
+			nil,
+			[]polyglot.Entry{
+				{Move: encodeMove("d2d4"), Weight: 10},
+				{Move: encodeMove("e2e4"), Weight: 20},
+				{Move: encodeMove("g1f3"), Weight: 5},
+			},
+			[]string{"e4", "d4", "Nf3"},
+			[]int{20, 10, 5},
+		},
+		{
+			"short_castling",
+			[]string{"e4", "e5", "Nf3", "Nc6", "Bc4", "Bc5"},
+			[]polyglot.Entry{{Move: encodeMove("e1h1"), Weight: 1}},
+			[]string{"O-O"},
+			[]int{1},
+		},
+		{
+			"long_castling",
+			[]string{"d4", "d5", "Nc3", "Nc6", "Bf4", "Bf5", "Qd2", "Qd7"},
+			[]polyglot.Entry{{Move: encodeMove("e1a1"), Weight: 1}},
+			[]string{"O-O-O"},
+			[]int{1},
+		},
+		{
+			"illegal_move",
+			nil,
+			[]polyglot.Entry{{Move: encodeMove("e2e5"), Weight: 1}, {Move: encodeMove("c2c4"), Weight: 1}},
+			[]string{"c4"},
+			[]int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := newBoard(t, tt.moves...)
+			book := newBook(t, withKey(polyglot.Key(board), tt.entries)...)
+
+			moves, err := book.Moves(board)
+			require.NoError(t, err)
+
+			sans := make([]string, 0, len(moves))
+			weights := make([]int, 0, len(moves))
+			for _, move := range moves {
+				sans = append(sans, move.Move.SAN())
+				weights = append(weights, move.Weight)
+
+				_, err := board.MakeMove(move.Move.SAN())
+				require.NoError(t, err)
+				_, err = board.UndoLastMove()
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, sans)
+			assert.Equal(t, tt.wantW, weights)
+		})
+	}
+}
+
+func TestBook_Moves_Promotion(t *testing.T) {
+	board, err := fen.Decode("4k3/1P6/8/8/8/8/8/4K3 w - - 0 1")
+	require.NoError(t, err)
+
+	book := newBook(t, withKey(polyglot.Key(board), []polyglot.Entry{
+		{Move: encodeMove("b7b8q"), Weight: 3},
+		{Move: encodeMove("b7b8n"), Weight: 1},
+	})...)
+
+	moves, err := book.Moves(board)
+	require.NoError(t, err)
+	require.Len(t, moves, 2)
+	assert.Equal(t, "b8=Q+", moves[0].Move.SAN())
+	assert.Equal(t, "b8=N", moves[1].Move.SAN())
+}
+
+func TestBook_Entries(t *testing.T) {
+	book := newBook(t,
+		polyglot.Entry{Key: 1, Move: encodeMove("e2e4"), Weight: 1},
+		polyglot.Entry{Key: 2, Move: encodeMove("d2d4"), Weight: 2, Learn: 7},
+		polyglot.Entry{Key: 2, Move: encodeMove("c2c4"), Weight: 3},
+		polyglot.Entry{Key: 5, Move: encodeMove("g1f3"), Weight: 4},
+	)
+
+	entries, err := book.Entries(2)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, polyglot.Entry{Key: 2, Move: encodeMove("d2d4"), Weight: 2, Learn: 7}, entries[0])
+	assert.Equal(t, "c2c4", entries[1].UCI())
+
+	for _, key := range []uint64{0, 3, 6} {
+		entries, err := book.Entries(key)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	}
+}
+
+func TestBook_BestMove(t *testing.T) {
+	board := standardchess.NewBoard()
+	book := newBook(t, withKey(polyglot.Key(board), []polyglot.Entry{
+		{Move: encodeMove("d2d4"), Weight: 10},
+		{Move: encodeMove("e2e4"), Weight: 20},
+	})...)
+
+	move, ok, err := book.BestMove(board)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "e4", move.SAN())
+
+	_, err = board.MakeMove("e4")
+	require.NoError(t, err)
+
+	_, ok, err = book.BestMove(board)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestBook_RandomMove(t *testing.T) {
+	board := standardchess.NewBoard()
+	book := newBook(t, withKey(polyglot.Key(board), []polyglot.Entry{
+		{Move: encodeMove("d2d4"), Weight: 1},
+		{Move: encodeMove("e2e4"), Weight: 3},
+		{Move: encodeMove("b1a3"), Weight: 0},
+	})...)
+
+	r := rand.New(rand.NewPCG(1, 2))
+	counts := make(map[string]int)
+	for range 400 {
+		move, ok, err := book.RandomMove(board, r)
+		require.NoError(t, err)
+		require.True(t, ok)
+		counts[move.SAN()]++
+	}
+
+	assert.Zero(t, counts["Na3"])
+	assert.InDelta(t, 300, counts["e4"], 40)
+	assert.InDelta(t, 100, counts["d4"], 40)
+}
+
+func TestBook_RandomMove_ZeroWeights(t *testing.T) {
+	board := standardchess.NewBoard()
+	book := newBook(t, withKey(polyglot.Key(board), []polyglot.Entry{
+		{Move: encodeMove("d2d4")},
+		{Move: encodeMove("e2e4")},
+	})...)
+
+	counts := make(map[string]int)
+	for range 100 {
+		move, ok, err := book.RandomMove(board, nil)
+		require.NoError(t, err)
+		require.True(t, ok)
+		counts[move.SAN()]++
+	}
+
+	assert.Equal(t, 100, counts["d4"]+counts["e4"])
+}
+
+func TestNewBook_InvalidSize(t *testing.T) {
+	_, err := polyglot.NewBook(bytes.NewReader(make([]byte, 20)), 20)
+	assert.ErrorIs(t, err, polyglot.ErrInvalidBook)
+}
+
+func TestOpen(t *testing.T) {
+	board := standardchess.NewBoard()
+	path := filepath.Join(t.TempDir(), "book.bin")
+	require.NoError(t, os.WriteFile(path, encodeEntries(withKey(polyglot.Key(board), []polyglot.Entry{
+		{Move: encodeMove("e2e4"), Weight: 1},
+	})), 0o600))
+
+	book, err := polyglot.Open(path)
+	require.NoError(t, err)
+	defer book.Close()
+
+	move, ok, err := book.BestMove(board)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "e4", move.SAN())
+}
+
+func TestOpen_NotExist(t *testing.T) {
+	_, err := polyglot.Open(filepath.Join(t.TempDir(), "book.bin"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func newBoard(t *testing.T, moves ...string) chess.Board {
+	t.Helper()
+
+	board := standardchess.NewBoard()
+	for _, move := range moves {
+		_, err := board.MakeMove(move)
+		require.NoError(t, err)
+	}
+
+	return board
+}
+
+func newBook(t *testing.T, entries ...polyglot.Entry) *polyglot.Book {
+	t.Helper()
+
+	slices.SortStableFunc(entries, func(a, b polyglot.Entry) int {
+		switch {
+		case a.Key < b.Key:
+			return -1
+		case a.Key > b.Key:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	data := encodeEntries(entries)
+	book, err := polyglot.NewBook(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	return book
+}
+
+func withKey(key uint64, entries []polyglot.Entry) []polyglot.Entry {
+	for i := range entries {
+		entries[i].Key = key
+	}
+
+	return entries
+}
+
+func encodeEntries(entries []polyglot.Entry) []byte {
+	data := make([]byte, 0, len(entries)*polyglot.EntrySize)
+	for _, entry := range entries {
+		data = binary.BigEndian.AppendUint64(data, entry.Key)
+		data = binary.BigEndian.AppendUint16(data, entry.Move)
+		data = binary.BigEndian.AppendUint16(data, entry.Weight)
+		data = binary.BigEndian.AppendUint32(data, entry.Learn)
+	}
+
+	return data
+}
+
+// encodeMove encodes the move in the UCI notation as Polyglot does.
+func encodeMove(uci string) uint16 {
+	move := uint16(uci[2]-'a') | uint16(uci[3]-'1')<<3 | uint16(uci[0]-'a')<<6 | uint16(uci[1]-'1')<<9
+	if len(uci) == 5 {
+		move |= uint16(map[byte]int{'n': 1, 'b': 2, 'r': 3, 'q': 4}[uci[4]]) << 12
+	}
+
+	return move
+}
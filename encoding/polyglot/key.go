@@ -0,0 +1,81 @@
+// Package polyglot contains the reader and the writer of the opening books in the Polyglot format.
+// The positions of a book are identified by the Polyglot Zobrist keys, see Key.
+package polyglot
+
+import (
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+)
+
+// pieceKinds are the indexes of the black pieces in the Polyglot Zobrist key,
+// the index of a white piece is greater by one.
+var pieceKinds = map[string]int{
+	standardchess.NotationPawn:   0,
+	standardchess.NotationKnight: 2,
+	standardchess.NotationBishop: 4,
+	standardchess.NotationRook:   6,
+	standardchess.NotationQueen:  8,
+	standardchess.NotationKing:   10,
+}
+
+// Key returns the Polyglot Zobrist key of the position on the 8x8 board.
+// The en passant file is taken into account only if a pawn of the side to move can capture en passant.
+func Key(board chess.Board) uint64 {
+	var key uint64
+	for position, p := range board.Squares().Iter() {
+		if p == nil {
+			continue
+		}
+
+		kind := pieceKinds[p.Notation()]
+		if p.Color().IsWhite() {
+			kind++
+		}
+		key ^= random64[randomPieceOffset+64*kind+squareIndex(position)]
+	}
+
+	f := fen.Encode(board)
+	for i, side := range [...]chess.Color{chess.ColorWhite, chess.ColorBlack} {
+		short, long := f.Castlings(side)
+		if short {
+			key ^= random64[randomCastlingOffset+2*i]
+		}
+		if long {
+			key ^= random64[randomCastlingOffset+2*i+1]
+		}
+	}
+
+	if square := f.EnPassantSquare(); !square.IsEmpty() && canCaptureEnPassant(board, square) {
+		key ^= random64[randomEnPassantOffset+int(square.File)-1]
+	}
+
+	if board.Turn().IsWhite() {
+		key ^= random64[randomTurnOffset]
+	}
+
+	return key
+}
+
+// canCaptureEnPassant reports whether a pawn of the side to move stands next to the pawn
+// which has passed the en passant square.
+func canCaptureEnPassant(board chess.Board, square chess.Position) bool {
+	rank := square.Rank - 1
+	if board.Turn().IsBlack() {
+		rank = square.Rank + 1
+	}
+
+	for _, file := range [...]chess.File{square.File - 1, square.File + 1} {
+		p, err := board.Squares().FindByPosition(chess.NewPosition(file, rank))
+		if err == nil && p != nil && p.Notation() == standardchess.NotationPawn && p.Color() == board.Turn() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// squareIndex returns the index of the square: 0 for a1, 1 for b1 and 63 for h8.
+func squareIndex(position chess.Position) int {
+	return 8*(int(position.Rank)-1) + int(position.File) - 1
+}
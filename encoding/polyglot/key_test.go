@@ -0,0 +1,39 @@
+package polyglot_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/polyglot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []string
+		want  uint64
+	}{
+		{"initial", nil, 0x463b96181691fc9c},
+		{"e4", []string{"e2e4"}, 0x823c9b50fd114196},
+		{"e4_d5", []string{"e2e4", "d7d5"}, 0x0756b94461c50fb0},
+		{"e4_d5_e5", []string{"e2e4", "d7d5", "e4e5"}, 0x662fafb965db29d4},
+		{"en_passant", []string{"e2e4", "d7d5", "e4e5", "f7f5"}, 0x22a48b5a8e47ff78},
+		{"king_moved", []string{"e2e4", "d7d5", "e4e5", "f7f5", "e1e2"}, 0x652a607ca3f242c1},
+		{"no_castlings", []string{"e2e4", "d7d5", "e4e5", "f7f5", "e1e2", "e8f7"}, 0x00fdd303c946bdd9},
+		{"black_en_passant", []string{"a2a4", "b7b5", "h2h4", "b5b4", "c2c4"}, 0x3c8123ea7b067637},
+		{"rook_moved", []string{"a2a4", "b7b5", "h2h4", "b5b4", "c2c4", "b4c3", "a1a3"}, 0x5c3f9b829b279560},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := standardchess.NewBoard()
+			for _, move := range tt.moves {
+				_, err := board.MakeMove(move)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, polyglot.Key(board))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package polyglot
+
+import (
+	"cmp"
+	"encoding/binary"
+	"io"
+	"slices"
+
+	"github.com/elaxer/standardchess"
+)
+
+// promotionCodes are the Polyglot codes of the promoted pieces by their notations.
+var promotionCodes = map[string]uint16{
+	standardchess.NotationKnight: 1,
+	standardchess.NotationBishop: 2,
+	standardchess.NotationRook:   3,
+	standardchess.NotationQueen:  4,
+}
+
+// Write writes the entries in the Polyglot format sorted by the keys,
+// the entries of a position are sorted by their weights in descending order.
+// The entries slice isn't modified.
+func Write(w io.Writer, entries []Entry) error {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b Entry) int {
+		if c := cmp.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(b.Weight, a.Weight)
+	})
+
+	buf := make([]byte, 0, len(sorted)*EntrySize)
+	for _, entry := range sorted {
+		buf = binary.BigEndian.AppendUint64(buf, entry.Key)
+		buf = binary.BigEndian.AppendUint16(buf, entry.Move)
+		buf = binary.BigEndian.AppendUint16(buf, entry.Weight)
+		buf = binary.BigEndian.AppendUint32(buf, entry.Learn)
+	}
+
+	_, err := w.Write(buf)
+
+	return err
+}
+
+// EncodeMove encodes the move as Polyglot does, castling is encoded as the king moving to its rook.
+func EncodeMove(move standardchess.Move) uint16 {
+	from, to := move.From, move.To
+	if move.IsCastling {
+		to = move.CastlingRook()
+	}
+
+	encoded := uint16(to.File-1) | uint16(to.Rank-1)<<3 | uint16(from.File-1)<<6 | uint16(from.Rank-1)<<9
+
+	return encoded | promotionCodes[move.PromotedPieceNotation]<<12
+}
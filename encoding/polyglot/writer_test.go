@@ -0,0 +1,93 @@
+package polyglot_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/encoding/polyglot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		chess960 bool
+		san      string
+		want     string
+	}{
+		{"normal", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", false, "Nf3", "g1f3"},
+		{"promotion", "4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", false, "b8=N", "b7b8n"},
+		{"short_castling", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", false, "O-O", "e1h1"},
+		{"long_castling", "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", false, "O-O-O", "e8a8"},
+		{"chess960_short_castling", "4k3/8/8/8/8/8/8/1R1K3R w HB - 0 1", true, "O-O", "d1h1"},
+		{"chess960_short_castling_rook_on_king_destination", "4k3/8/8/8/8/8/8/5KR1 w G - 0 1", true, "O-O", "f1g1"},
+		{"chess960_long_castling_rook_on_king_destination", "4k3/8/8/8/8/8/8/2RK4 w C - 0 1", true, "O-O-O", "d1c1"},
+		{"chess960_long_castling_king_on_destination", "4k3/8/8/8/8/8/8/1RK5 w B - 0 1", true, "O-O-O", "c1b1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decode := fen.Decode
+			if tt.chess960 {
+				decode = fen.DecodeChess960
+			}
+			board, err := decode(tt.fen)
+			require.NoError(t, err)
+
+			for _, move := range standardchess.MoveList(board) {
+				if move.SAN() == tt.san || move.SAN() == tt.san+"+" {
+					assert.Equal(t, tt.want, polyglot.Entry{Move: polyglot.EncodeMove(move)}.UCI())
+
+					return
+				}
+			}
+			t.Fatalf("no move %s", tt.san)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	board := newBoard(t, "e4", "e5", "Nf3", "Nc6", "Bc4", "Bc5")
+	legalMoves := standardchess.MoveList(board)
+	entries := make([]polyglot.Entry, 0, len(legalMoves)+1)
+	for i, move := range legalMoves {
+		entries = append(entries, polyglot.Entry{
+			Key:    polyglot.Key(board),
+			Move:   polyglot.EncodeMove(move),
+			Weight: uint16(i),
+		})
+	}
+	entries = append(entries, polyglot.Entry{Key: 1, Move: 1, Weight: 1, Learn: 2})
+
+	var buf bytes.Buffer
+	require.NoError(t, polyglot.Write(&buf, entries))
+	require.Equal(t, len(entries)*polyglot.EntrySize, buf.Len())
+
+	book, err := polyglot.NewBook(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	first, err := book.Entries(1)
+	require.NoError(t, err)
+	assert.Equal(t, []polyglot.Entry{{Key: 1, Move: 1, Weight: 1, Learn: 2}}, first)
+
+	moves, err := book.Moves(board)
+	require.NoError(t, err)
+	require.Len(t, moves, len(entries)-1)
+	for i, move := range moves {
+		assert.Equal(t, len(moves)-1-i, move.Weight)
+		assert.Equal(t, legalMoves[move.Weight].SAN(), move.Move.SAN())
+	}
+	assert.Contains(t, sans(moves), "O-O")
+}
+
+func sans(moves []polyglot.BookMove) []string {
+	result := make([]string, 0, len(moves))
+	for _, move := range moves {
+		result = append(result, move.Move.SAN())
+	}
+
+	return result
+}
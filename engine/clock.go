@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"math"
+	"time"
+)
+
+// NoClock is the remaining time of the moves played without the clock, see MoveTime.
+const NoClock time.Duration = math.MinInt64
+
+const (
+	// defaultMovesToGo is the number of the moves the remaining time is shared by
+	// if the time control doesn't tell the number of the moves to the next control.
+	defaultMovesToGo = 30
+	// moveOverhead is the time reserved for the communication with the opponent, e.g. a GUI.
+	moveOverhead = 50 * time.Millisecond
+)
+
+// MoveTime returns the time of the search of a move played with the clock:
+// the share of the remaining time and the most of the increment.
+// movesToGo is the number of the moves to the next time control, 30 moves are expected if it isn't positive.
+// It's zero if the remaining time is NoClock, so the time isn't limited.
+// The shortest time is returned if there is no time left on the clock, e.g. the flag has fallen.
+func MoveTime(remaining, increment time.Duration, movesToGo int) time.Duration {
+	if remaining == NoClock {
+		return 0
+	}
+	if movesToGo <= 0 {
+		movesToGo = defaultMovesToGo
+	}
+
+	share := remaining/time.Duration(movesToGo) + increment*3/4
+
+	return max(min(share, remaining-moveOverhead), time.Millisecond)
+}
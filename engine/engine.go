@@ -0,0 +1,129 @@
+// Package engine contains a built-in chess engine searching the best move on a board
+// by iterative deepening alpha-beta search with quiescence search and a transposition table.
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+)
+
+// DefaultHashSize is the default number of entries of the transposition table.
+const DefaultHashSize = 1 << 20
+
+// MaxDepth is the maximum depth of the search.
+const MaxDepth = 64
+
+var ErrNoMoves = errors.New("there are no legal moves")
+
+// Limits bound the search. Zero values mean no limit.
+// The search is also stopped when its context is done.
+type Limits struct {
+	// Depth is the maximum depth of the search in plies, MaxDepth if it's zero.
+	Depth int
+	// Nodes is the maximum number of the searched positions.
+	Nodes int
+	// Time is the maximum duration of the search.
+	Time time.Duration
+}
+
+// Result is the result of the search.
+type Result struct {
+	// BestMove is the best move found.
+	BestMove standardchess.Move
+	// PV is the principal variation: the sequence of the best moves of both sides starting from BestMove.
+	PV []standardchess.Move
+	// Score is the evaluation of the position after the principal variation
+	// from the point of view of the side to move.
+	Score Score
+	// Depth is the depth of the last completed iteration.
+	Depth int
+	// Nodes is the number of the searched positions.
+	Nodes int
+	// Duration is the time spent on the search.
+	Duration time.Duration
+}
+
+// Engine searches the best moves. It keeps the transposition table between searches,
+// so the next search of the same game is faster.
+// An engine must not run several searches concurrently.
+type Engine struct {
+	// Progress is called with the result of each completed iteration of the search if it's set.
+	Progress func(Result)
+
+	tt *transpositionTable
+}
+
+// New creates an engine with the transposition table of the size,
+// DefaultHashSize is used if the size isn't positive.
+func New(hashSize int) *Engine {
+	if hashSize <= 0 {
+		hashSize = DefaultHashSize
+	}
+
+	return &Engine{tt: newTranspositionTable(hashSize)}
+}
+
+// Clear clears the transposition table, e.g. before a new game.
+func (e *Engine) Clear() {
+	e.tt.clear()
+}
+
+// Search searches the best move on the board within the limits.
+// The moves are made and undone on the board during the search,
+// so the board must not be used concurrently; it's restored when the search is finished.
+// Boards created by the standardchess package are much faster searched with the bitboard backend.
+//
+// The search deepens iteratively and returns the result of the last completed iteration
+// when a limit is reached or the context is done, so a move is returned even if the search is stopped early.
+// ErrNoMoves is returned if the side to move has no legal moves.
+func (e *Engine) Search(ctx context.Context, board chess.Board, limits Limits) (Result, error) {
+	s := newSearch(ctx, board, e.tt, limits)
+
+	moves := s.rootMoves()
+	if len(moves) == 0 {
+		return Result{}, ErrNoMoves
+	}
+
+	maxDepth := limits.Depth
+	if maxDepth <= 0 || maxDepth > MaxDepth {
+		maxDepth = MaxDepth
+	}
+
+	result := Result{}
+	for depth := 1; depth <= maxDepth; depth++ {
+		score := s.negamax(depth, 0, -ScoreInfinity, ScoreInfinity)
+		if s.stopped {
+			break
+		}
+
+		pv := s.pv.line()
+		if len(pv) == 0 {
+			break
+		}
+
+		result = s.result(pv, score, depth)
+		if e.Progress != nil {
+			e.Progress(result)
+		}
+
+		if score.IsMate() {
+			if mate, _ := score.Mate(); mate > 0 && 2*mate-1 <= depth {
+				break
+			}
+		}
+	}
+
+	if result.Depth == 0 {
+		// Not even the first iteration is completed, the first legal move is returned.
+		result = s.result([]string{moves[0].UCI()}, Evaluate(board), 0)
+	}
+
+	result.Nodes = s.nodes
+	result.Duration = time.Since(s.start)
+
+	return result, nil
+}
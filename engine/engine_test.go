@@ -0,0 +1,187 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_Search(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		depth    int
+		wantMove string
+		wantMate int
+	}{
+		{"mate_in_one", "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", 2, "Ra8#", 1},
+		{"mate_in_two", "k7/8/2K5/8/8/8/8/7R w - - 0 1", 4, "", 2},
+		{"back_rank_defence", "r6k/8/8/8/8/8/5PPP/6K1 w - - 0 1", 3, "", 0},
+		{"hanging_queen", "4k3/8/8/3q4/8/8/8/3RK3 w - - 0 1", 3, "Rxd5", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := mustDecodeFEN(t, tt.fen)
+			initFEN := fen.Encode(board).String()
+
+			result, err := engine.New(1<<16).Search(context.Background(), board, engine.Limits{Depth: tt.depth})
+			require.NoError(t, err)
+
+			if tt.wantMove != "" {
+				assert.Equal(t, tt.wantMove, result.BestMove.SAN())
+			}
+
+			mate, _ := result.Score.Mate()
+			assert.Equal(t, tt.wantMate, mate)
+
+			require.NotEmpty(t, result.PV)
+			assert.Equal(t, result.BestMove, result.PV[0])
+			assert.Equal(t, initFEN, fen.Encode(board).String())
+		})
+	}
+}
+
+func TestEngine_Search_DefendedPawn(t *testing.T) {
+	board := mustDecodeFEN(t, "4k3/8/2p5/3p4/8/8/8/3QK3 w - - 0 1")
+
+	result, err := engine.New(1<<16).Search(context.Background(), board, engine.Limits{Depth: 3})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, "Qxd5", result.BestMove.SAN())
+}
+
+func TestEngine_Search_Limits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits engine.Limits
+	}{
+		{"depth", engine.Limits{Depth: 2}},
+		{"nodes", engine.Limits{Nodes: 500}},
+		{"time", engine.Limits{Time: 50 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := standardchess.NewBoard()
+			require.NoError(t, standardchess.SetBackend(board, standardchess.BackendBitboard))
+
+			result, err := engine.New(1<<16).Search(context.Background(), board, tt.limits)
+			require.NoError(t, err)
+
+			assert.NotEmpty(t, result.BestMove.SAN())
+			assert.Empty(t, board.MoveHistory())
+			if tt.limits.Depth > 0 {
+				assert.Equal(t, tt.limits.Depth, result.Depth)
+			}
+			if tt.limits.Nodes > 0 {
+				assert.LessOrEqual(t, result.Nodes, tt.limits.Nodes)
+			}
+			if tt.limits.Time > 0 {
+				assert.Less(t, result.Duration, tt.limits.Time+time.Second)
+			}
+		})
+	}
+}
+
+func TestEngine_Search_Cancel(t *testing.T) {
+	board := standardchess.NewBoard()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := engine.New(1<<16).Search(ctx, board, engine.Limits{})
+	require.NoError(t, err)
+
+	assert.Zero(t, result.Depth)
+	assert.NotEmpty(t, result.BestMove.SAN())
+	assert.Empty(t, board.MoveHistory())
+}
+
+func TestEngine_Search_Progress(t *testing.T) {
+	e := engine.New(1 << 16)
+
+	var depths []int
+	e.Progress = func(result engine.Result) {
+		depths = append(depths, result.Depth)
+	}
+
+	_, err := e.Search(context.Background(), standardchess.NewBoard(), engine.Limits{Depth: 3})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 2, 3}, depths)
+}
+
+func TestEngine_Search_NoMoves(t *testing.T) {
+	board := mustDecodeFEN(t, "7k/5Q2/6K1/8/8/8/8/8 b - - 0 1")
+
+	_, err := engine.New(1<<16).Search(context.Background(), board, engine.Limits{Depth: 1})
+	assert.ErrorIs(t, err, engine.ErrNoMoves)
+}
+
+func TestEvaluate(t *testing.T) {
+	assert.Zero(t, engine.Evaluate(standardchess.NewBoard()))
+
+	white := mustDecodeFEN(t, "4k3/8/8/8/8/8/8/3QK3 w - - 0 1")
+	black := mustDecodeFEN(t, "4k3/8/8/8/8/8/8/3QK3 b - - 0 1")
+
+	assert.Positive(t, engine.Evaluate(white))
+	assert.Equal(t, -engine.Evaluate(white), engine.Evaluate(black))
+}
+
+func TestScore_String(t *testing.T) {
+	tests := []struct {
+		score engine.Score
+		want  string
+	}{
+		{0, "+0.00"},
+		{125, "+1.25"},
+		{-50, "-0.50"},
+		{engine.ScoreMate - 1, "#1"},
+		{engine.ScoreMate - 3, "#2"},
+		{-engine.ScoreMate + 2, "#-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.score.String())
+		})
+	}
+}
+
+func mustDecodeFEN(t *testing.T, str string) standardchess.Board {
+	t.Helper()
+
+	board, err := fen.Decode(str)
+	require.NoError(t, err)
+	require.NoError(t, standardchess.SetBackend(board, standardchess.BackendBitboard))
+
+	return board
+}
+
+func TestMoveTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining time.Duration
+		increment time.Duration
+		movesToGo int
+		want      time.Duration
+	}{
+		{"no clock", engine.NoClock, time.Second, 0, 0},
+		{"empty clock", 0, time.Second, 0, time.Millisecond},
+		{"negative clock", -time.Second, time.Second, 0, time.Millisecond},
+		{"sudden death", time.Minute, 0, 0, 2 * time.Second},
+		{"increment", time.Minute, 4 * time.Second, 0, 5 * time.Second},
+		{"moves to go", time.Minute, 0, 10, 6 * time.Second},
+		{"last move", time.Second, 0, 1, 950 * time.Millisecond},
+		{"no time left", 10 * time.Millisecond, 0, 1, time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, engine.MoveTime(tt.remaining, tt.increment, tt.movesToGo))
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+)
+
+const (
+	// ScoreMate is the score of the checkmated position for the winner.
+	// Mates found by the search have the score decreased by the number of plies to the mate.
+	ScoreMate Score = 1_000_000
+	// ScoreInfinity is greater than any score.
+	ScoreInfinity Score = ScoreMate + 1
+)
+
+// pieceValueMultiplier converts the weights of the pieces to centipawns.
+const pieceValueMultiplier = 100
+
+// Score is the evaluation of a position in centipawns from the point of view of the side to move.
+type Score int
+
+// Evaluate returns the static evaluation of the position on the board from the point of view of the side to move.
+// It's the material by the piece weights with the bonuses for advanced pawns and centralized pieces.
+// The kings aren't counted, terminal states aren't taken into account.
+func Evaluate(board chess.Board) Score {
+	edge := board.Squares().EdgePosition()
+
+	var score Score
+	for position, p := range board.Squares().Iter() {
+		if p == nil {
+			continue
+		}
+
+		value := pieceValue(p) + positionalBonus(p, position, edge)
+		if p.Color() == board.Turn() {
+			score += value
+		} else {
+			score -= value
+		}
+	}
+
+	return score
+}
+
+// IsMate reports whether the score means a forced checkmate.
+func (s Score) IsMate() bool {
+	return s >= ScoreMate-MaxDepth*2 || s <= -ScoreMate+MaxDepth*2
+}
+
+// Mate returns the number of moves to the checkmate,
+// negative if the side to move is checkmated.
+// The second value is false if the score doesn't mean a checkmate.
+func (s Score) Mate() (int, bool) {
+	if !s.IsMate() {
+		return 0, false
+	}
+
+	if s > 0 {
+		return int(ScoreMate-s+1) / 2, true
+	}
+
+	return -int(ScoreMate+s) / 2, true
+}
+
+// String returns the score in pawns, e.g. "+1.25", or the number of moves to the checkmate, e.g. "#3" or "#-2".
+func (s Score) String() string {
+	if mate, ok := s.Mate(); ok {
+		return fmt.Sprintf("#%d", mate)
+	}
+
+	return fmt.Sprintf("%+.2f", float64(s)/pieceValueMultiplier)
+}
+
+func pieceValue(p chess.Piece) Score {
+	if p.Notation() == standardchess.NotationKing {
+		return 0
+	}
+
+	return Score(p.Weight()) * pieceValueMultiplier
+}
+
+// positionalBonus returns the bonus for the pawns advanced towards the promotion
+// and for the knights, bishops and queens closer to the center of the board.
+func positionalBonus(p chess.Piece, position, edge chess.Position) Score {
+	switch p.Notation() {
+	case standardchess.NotationPawn:
+		advance := int(position.Rank) - 2
+		if p.Color().IsBlack() {
+			advance = int(edge.Rank) - 1 - int(position.Rank)
+		}
+
+		return Score(max(advance, 0) * 5)
+	case standardchess.NotationKnight:
+		return centrality(position, edge) * 4
+	case standardchess.NotationBishop:
+		return centrality(position, edge) * 2
+	case standardchess.NotationQueen:
+		return centrality(position, edge)
+	default:
+		return 0
+	}
+}
+
+// centrality returns the distance of the position from the corners of the board:
+// zero for the corners and the greatest in the center.
+func centrality(position, edge chess.Position) Score {
+	fileDistance := abs(2*int(position.File) - int(edge.File) - 1)
+	rankDistance := abs(2*int(position.Rank) - int(edge.Rank) - 1)
+
+	return Score(int(edge.File)+int(edge.Rank)-2-fileDistance-rankDistance) / 2
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
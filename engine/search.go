@@ -0,0 +1,349 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+)
+
+// maxPly is the maximum distance from the root including the quiescence search.
+const maxPly = MaxDepth * 2
+
+// checkInterval is the number of nodes between the checks of the time and the context.
+const checkInterval = 1024
+
+var promotionValues = map[string]int{
+	standardchess.NotationQueen:  standardchess.WeightQueen,
+	standardchess.NotationRook:   standardchess.WeightRook,
+	standardchess.NotationBishop: standardchess.WeightBishop,
+	standardchess.NotationKnight: standardchess.WeightKnight,
+}
+
+// hasher is implemented by the boards providing the Zobrist hash of the position.
+type hasher interface {
+	Hash() uint64
+}
+
+// search is the state of a single search.
+type search struct {
+	ctx      context.Context
+	board    chess.Board
+	tt       *transpositionTable
+	limits   Limits
+	start    time.Time
+	deadline time.Time
+
+	nodes   int
+	stopped bool
+	pv      pvTable
+}
+
+// pvTable is the triangular table of the principal variations of each ply.
+type pvTable struct {
+	moves   [maxPly + 1][maxPly + 1]string
+	lengths [maxPly + 1]int
+}
+
+func newSearch(ctx context.Context, board chess.Board, tt *transpositionTable, limits Limits) *search {
+	s := &search{ctx: ctx, board: board, tt: tt, limits: limits, start: time.Now()}
+	if limits.Time > 0 {
+		s.deadline = s.start.Add(limits.Time)
+	}
+
+	return s
+}
+
+// rootMoves returns the legal moves of the side to move.
+func (s *search) rootMoves() []standardchess.Move {
+	if s.board.State().Type().IsTerminal() {
+		return nil
+	}
+
+	moves := make([]standardchess.Move, 0, 64)
+	for _, move := range standardchess.PseudoMoveList(s.board) {
+		if !s.makeMove(move) {
+			continue
+		}
+
+		s.undoMove()
+		moves = append(moves, move)
+	}
+
+	return moves
+}
+
+func (s *search) negamax(depth, ply int, alpha, beta Score) Score {
+	s.pv.lengths[ply] = 0
+	if s.shouldStop() {
+		return 0
+	}
+
+	s.nodes++
+
+	state := s.board.State()
+	if ply > 0 && state.Type().IsTerminal() {
+		return terminalScore(state, ply)
+	}
+	if ply >= maxPly {
+		return Evaluate(s.board)
+	}
+
+	isCheck := state == standardchess.StateCheck
+	if isCheck {
+		depth++
+	}
+	if depth <= 0 {
+		return s.quiescence(ply, alpha, beta)
+	}
+
+	hash, hasHash := s.hash()
+	ttMove := ""
+	if entry, ok := s.tt.probe(hash); hasHash && ok {
+		ttMove = entry.move
+		if score, ok := entry.cutoff(depth, ply, alpha, beta); ok && ply > 0 {
+			return score
+		}
+	}
+
+	alphaOrig := alpha
+	best, bestMove := -ScoreInfinity, ""
+	for _, move := range orderMoves(standardchess.PseudoMoveList(s.board), ttMove) {
+		if !s.makeMove(move) {
+			continue
+		}
+
+		score := -s.negamax(depth-1, ply+1, -beta, -alpha)
+		s.undoMove()
+
+		if s.stopped {
+			return 0
+		}
+		if score <= best {
+			continue
+		}
+
+		best, bestMove = score, move.UCI()
+		if score > alpha {
+			alpha = score
+			s.pv.update(ply, bestMove)
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	if bestMove == "" {
+		// All the moves are rejected by the board.
+		if isCheck {
+			return -ScoreMate + Score(ply)
+		}
+
+		return 0
+	}
+
+	if hasHash {
+		s.tt.store(hash, bestMove, best, depth, ply, boundOf(best, alphaOrig, beta))
+	}
+
+	return best
+}
+
+// quiescence searches captures and promotions only until the position is quiet,
+// so that the static evaluation isn't taken in the middle of an exchange.
+func (s *search) quiescence(ply int, alpha, beta Score) Score {
+	s.pv.lengths[ply] = 0
+	if s.shouldStop() {
+		return 0
+	}
+
+	s.nodes++
+
+	if state := s.board.State(); state.Type().IsTerminal() {
+		return terminalScore(state, ply)
+	}
+
+	standPat := Evaluate(s.board)
+	if standPat >= beta || ply >= maxPly {
+		return standPat
+	}
+	alpha = max(alpha, standPat)
+
+	moves := slices.DeleteFunc(standardchess.PseudoMoveList(s.board), func(move standardchess.Move) bool {
+		return !move.IsCapture() && !move.IsPromotion()
+	})
+	for _, move := range orderMoves(moves, "") {
+		if !s.makeMove(move) {
+			continue
+		}
+
+		score := -s.quiescence(ply+1, -beta, -alpha)
+		s.undoMove()
+
+		if s.stopped {
+			return 0
+		}
+		if score > alpha {
+			alpha = score
+			s.pv.update(ply, move.UCI())
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	return alpha
+}
+
+// shouldStop reports whether a limit of the search is reached or its context is done.
+func (s *search) shouldStop() bool {
+	if s.stopped {
+		return true
+	}
+
+	switch {
+	case s.limits.Nodes > 0 && s.nodes >= s.limits.Nodes:
+		s.stopped = true
+	case s.nodes%checkInterval == 0:
+		s.stopped = s.ctx.Err() != nil || (!s.deadline.IsZero() && time.Now().After(s.deadline))
+	}
+
+	return s.stopped
+}
+
+func (s *search) makeMove(move standardchess.Move) bool {
+	_, err := s.board.MakeMove(move.UCI())
+
+	return err == nil
+}
+
+func (s *search) undoMove() {
+	if _, err := s.board.UndoLastMove(); err != nil {
+		panic(err)
+	}
+}
+
+func (s *search) hash() (uint64, bool) {
+	if h, ok := s.board.(hasher); ok {
+		return h.Hash(), true
+	}
+
+	return 0, false
+}
+
+// result returns the result with the principal variation given in UCI notation.
+// The moves of the variation are made on the board to find out their SAN and undone then.
+func (s *search) result(pv []string, score Score, depth int) Result {
+	result := Result{Score: score, Depth: depth, Nodes: s.nodes, Duration: time.Since(s.start)}
+	for _, uci := range pv {
+		moves := standardchess.MoveList(s.board)
+		i := slices.IndexFunc(moves, func(move standardchess.Move) bool {
+			return move.UCI() == uci
+		})
+		if i < 0 {
+			break
+		}
+
+		move := moves[i]
+		if !s.makeMove(move) {
+			break
+		}
+		result.PV = append(result.PV, move)
+	}
+
+	for range result.PV {
+		s.undoMove()
+	}
+
+	if len(result.PV) > 0 {
+		result.BestMove = result.PV[0]
+	}
+
+	return result
+}
+
+func (t *pvTable) update(ply int, move string) {
+	t.moves[ply][0] = move
+	copy(t.moves[ply][1:], t.moves[ply+1][:t.lengths[ply+1]])
+	t.lengths[ply] = t.lengths[ply+1] + 1
+}
+
+// line returns the principal variation of the root.
+func (t *pvTable) line() []string {
+	return slices.Clone(t.moves[0][:t.lengths[0]])
+}
+
+func (e ttEntry) cutoff(depth, ply int, alpha, beta Score) (Score, bool) {
+	if int(e.depth) < depth {
+		return 0, false
+	}
+
+	score := fromTT(e.score, ply)
+	switch {
+	case e.bound == boundExact,
+		e.bound == boundLower && score >= beta,
+		e.bound == boundUpper && score <= alpha:
+		return score, true
+	default:
+		return 0, false
+	}
+}
+
+func boundOf(score, alpha, beta Score) bound {
+	switch {
+	case score <= alpha:
+		return boundUpper
+	case score >= beta:
+		return boundLower
+	default:
+		return boundExact
+	}
+}
+
+func terminalScore(state chess.State, ply int) Score {
+	if state == standardchess.StateCheckmate {
+		return -ScoreMate + Score(ply)
+	}
+
+	return 0
+}
+
+// orderMoves sorts the moves so that the best ones are likely searched first:
+// the move from the transposition table, promotions, captures of the most valuable pieces
+// by the least valuable ones and then the other moves.
+func orderMoves(moves []standardchess.Move, first string) []standardchess.Move {
+	type scoredMove struct {
+		move  standardchess.Move
+		order int
+	}
+
+	scored := make([]scoredMove, len(moves))
+	for i, move := range moves {
+		scored[i] = scoredMove{move, moveOrder(move, first)}
+	}
+
+	slices.SortStableFunc(scored, func(a, b scoredMove) int {
+		return b.order - a.order
+	})
+
+	for i := range scored {
+		moves[i] = scored[i].move
+	}
+
+	return moves
+}
+
+func moveOrder(move standardchess.Move, first string) int {
+	switch {
+	case first != "" && move.UCI() == first:
+		return 1 << 20
+	case move.IsPromotion():
+		return 1<<16 + promotionValues[move.PromotedPieceNotation]
+	case move.IsCapture():
+		return 1<<12 + int(pieceValue(move.CapturedPiece))*16 - int(pieceValue(move.Piece))/pieceValueMultiplier
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,73 @@
+package engine
+
+const (
+	boundExact bound = iota + 1
+	// boundLower means the score is at least the stored one: the search failed high.
+	boundLower
+	// boundUpper means the score is at most the stored one: the search failed low.
+	boundUpper
+)
+
+type bound uint8
+
+type ttEntry struct {
+	hash  uint64
+	move  string
+	score Score
+	depth int8
+	bound bound
+}
+
+// transpositionTable stores the results of the searched positions by their hashes.
+// A new entry replaces the old one with the same index.
+type transpositionTable struct {
+	entries []ttEntry
+}
+
+func newTranspositionTable(size int) *transpositionTable {
+	return &transpositionTable{entries: make([]ttEntry, size)}
+}
+
+func (t *transpositionTable) probe(hash uint64) (ttEntry, bool) {
+	entry := t.entries[hash%uint64(len(t.entries))]
+
+	return entry, entry.bound != 0 && entry.hash == hash
+}
+
+// store stores the result of the position searched at the ply.
+// The mate scores are stored relative to the position, so that they can be reused at another ply.
+func (t *transpositionTable) store(hash uint64, move string, score Score, depth, ply int, bound bound) {
+	entry := &t.entries[hash%uint64(len(t.entries))]
+	if entry.hash == hash && int(entry.depth) > depth && bound != boundExact {
+		return
+	}
+
+	//nolint:gosec
+	*entry = ttEntry{hash: hash, move: move, score: toTT(score, ply), depth: int8(depth), bound: bound}
+}
+
+func (t *transpositionTable) clear() {
+	clear(t.entries)
+}
+
+func toTT(score Score, ply int) Score {
+	switch {
+	case score >= ScoreMate-MaxDepth*2:
+		return score + Score(ply)
+	case score <= -ScoreMate+MaxDepth*2:
+		return score - Score(ply)
+	default:
+		return score
+	}
+}
+
+func fromTT(score Score, ply int) Score {
+	switch {
+	case score >= ScoreMate-MaxDepth*2:
+		return score - Score(ply)
+	case score <= -ScoreMate+MaxDepth*2:
+		return score + Score(ply)
+	default:
+		return score
+	}
+}
@@ -0,0 +1,327 @@
+// Package game contains a chess game played on a board with the clocks of the players under a time control.
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/pgn"
+)
+
+var (
+	ErrGameOver             = errors.New("the game is over")
+	ErrFlagFell             = errors.New("the flag has fallen")
+	ErrInvalidTimeControl   = errors.New("invalid time control")
+	ErrUnknownTimeControl   = errors.New("unknown time control")
+	ErrSandclockTimeControl = errors.New("sandclock time control isn't supported")
+	ErrInvalidResult        = errors.New("invalid result")
+)
+
+// Game is a game played on a board with the clocks of the players.
+// The clock of the side to move runs from the creation of the game and from each move.
+// A game must not be used concurrently.
+type Game struct {
+	board   chess.Board
+	control TimeControl
+	now     func() time.Time
+
+	clocks    map[chess.Color]time.Duration
+	moves     map[chess.Color]int
+	turnStart time.Time
+	records   []MoveRecord
+
+	flagged      bool
+	flaggedColor chess.Color
+	outcome      *Outcome
+
+	drawOffer request
+	takeback  request
+}
+
+// MoveRecord is a move of the game with the times of the clock.
+type MoveRecord struct {
+	Move chess.Move
+	// Clock is the remaining time of the player after the move including the increment.
+	Clock time.Duration
+	// Elapsed is the time spent on the move.
+	Elapsed time.Duration
+}
+
+// Option configures New.
+type Option func(*Game)
+
+// New creates the game played on the board under the time control, the game isn't timed if the control is empty.
+// The board must not be changed but by Move.
+func New(board chess.Board, control TimeControl, opts ...Option) *Game {
+	g := &Game{
+		board:   board,
+		control: slices.Clone(control),
+		now:     time.Now,
+		clocks:  make(map[chess.Color]time.Duration, 2),
+		moves:   make(map[chess.Color]int, 2),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if len(control) > 0 {
+		g.clocks[chess.ColorWhite] = control[0].Time
+		g.clocks[chess.ColorBlack] = control[0].Time
+	}
+	g.turnStart = g.now()
+
+	return g
+}
+
+// WithClock makes the game read the current time from the function instead of time.Now,
+// e.g. to control the time in tests.
+func WithClock(now func() time.Time) Option {
+	return func(g *Game) {
+		g.now = now
+	}
+}
+
+// Board returns the board of the game.
+func (g *Game) Board() chess.Board {
+	return g.board
+}
+
+// TimeControl returns the time control of the game.
+func (g *Game) TimeControl() TimeControl {
+	return slices.Clone(g.control)
+}
+
+// Move makes the move of the side to move in SAN or UCI notation and stops its clock.
+// ErrFlagFell is returned if a player has run out of time before the move.
+func (g *Game) Move(move string) (chess.Move, error) {
+	now := g.now()
+	if g.checkFlag(now) {
+		return nil, fmt.Errorf("%w: %s", ErrFlagFell, g.flaggedColor)
+	}
+	if g.ended() {
+		return nil, ErrGameOver
+	}
+
+	color := g.board.Turn()
+	made, err := g.board.MakeMove(move)
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := now.Sub(g.turnStart)
+	g.turnStart = now
+	g.records = append(g.records, MoveRecord{Move: made, Clock: g.stopClock(color, elapsed), Elapsed: elapsed})
+	g.moves[color]++
+
+	// The move declines the draw offer of the opponent and any takeback request.
+	if g.drawOffer.pending && g.drawOffer.color != color {
+		g.drawOffer.pending = false
+	}
+	g.takeback.pending = false
+
+	return made, nil
+}
+
+// Remaining returns the remaining time of the player, the time of the side to move is counted at the moment.
+// It's zero if the game isn't timed.
+func (g *Game) Remaining(color chess.Color) time.Duration {
+	if len(g.control) == 0 {
+		return 0
+	}
+	if color != g.board.Turn() || g.ended() {
+		return g.clocks[color]
+	}
+
+	return max(g.remaining(g.now()), 0)
+}
+
+// Flag reports whether a player has run out of time and returns its color.
+// The clock of the side to move is checked at the moment, so a flag fall is detected without a move.
+func (g *Game) Flag() (chess.Color, bool) {
+	g.checkFlag(g.now())
+
+	return g.flaggedColor, g.flagged
+}
+
+// IsOver reports whether the game is over.
+func (g *Game) IsOver() bool {
+	_, over := g.Outcome()
+
+	return over
+}
+
+// Outcome returns the outcome of the game, the second value is false if the game isn't over.
+// A player who has run out of time loses the game unless the opponent can't checkmate
+// by any series of legal moves, then the game is drawn.
+func (g *Game) Outcome() (Outcome, bool) {
+	g.checkFlag(g.now())
+	if g.outcome != nil {
+		return *g.outcome, true
+	}
+
+	return OutcomeFromBoard(g.board)
+}
+
+// Result returns the result of the game, pgn.ResultInProcess if the game isn't over.
+func (g *Game) Result() pgn.Result {
+	outcome, over := g.Outcome()
+	if !over {
+		return pgn.ResultInProcess
+	}
+
+	return outcome.Result
+}
+
+// Resign ends the game by the resignation of the player.
+func (g *Game) Resign(color chess.Color) error {
+	return g.end(Outcome{Result: winResult(!color), Method: MethodResignation, Reason: colorName(color) + " resigns"})
+}
+
+// Forfeit ends the game by the loss of the player for a rules infraction described by the reason.
+func (g *Game) Forfeit(color chess.Color, reason string) error {
+	return g.end(Outcome{Result: winResult(!color), Method: MethodForfeit, Reason: reason})
+}
+
+// Abandon ends the game by the loss of the player who has left it.
+func (g *Game) Abandon(color chess.Color) error {
+	return g.end(Outcome{Result: winResult(!color), Method: MethodAbandonment, Reason: colorName(color) + " abandons"})
+}
+
+// Adjudicate ends the game by the result decided by an arbiter or a program for the reason.
+func (g *Game) Adjudicate(result pgn.Result, reason string) error {
+	if result != pgn.ResultWinWhite && result != pgn.ResultWinBlack && result != pgn.ResultDraw {
+		return fmt.Errorf("%w: %s", ErrInvalidResult, result)
+	}
+
+	return g.end(Outcome{Result: result, Method: MethodAdjudication, Reason: reason})
+}
+
+// Records returns the moves of the game with the times of the clock.
+func (g *Game) Records() []MoveRecord {
+	return slices.Clone(g.records)
+}
+
+// PGN returns the PGN of the game with the headers and the result of the game, see pgn.Encode.
+// The TimeControl and Termination headers are added unless they're given or the game isn't timed or over,
+// each move is commented with the remaining and the elapsed times, e.g. "[%clk 0:04:58] [%emt 0:00:02]".
+func (g *Game) PGN(headers pgn.Headers) pgn.PGN {
+	headers = slices.Clone(headers)
+	if _, ok := headers.Get(pgn.HeaderTimeControl); !ok && len(g.control) > 0 {
+		headers = append(headers, pgn.NewHeader(pgn.HeaderTimeControl, g.control.String()))
+	}
+	outcome, over := g.Outcome()
+	if _, ok := headers.Get(pgn.HeaderTermination); !ok && over {
+		headers = append(headers, pgn.NewHeader(pgn.HeaderTermination, outcome.Termination()))
+	}
+
+	p := pgn.Encode(headers, g.board, g.Result())
+	for i, node := range p.Root().Mainline() {
+		if i < len(g.records) {
+			record := g.records[i]
+			node.Comments = append(node.Comments,
+				fmt.Sprintf("[%%clk %s] [%%emt %s]", formatClock(record.Clock), formatClock(record.Elapsed)))
+		}
+	}
+
+	return p
+}
+
+// ended reports whether the game is over without checking the clock.
+func (g *Game) ended() bool {
+	return g.outcome != nil || g.board.State().Type().IsTerminal()
+}
+
+// end ends the game by the outcome and stops the clock of the side to move.
+func (g *Game) end(outcome Outcome) error {
+	if g.IsOver() {
+		return ErrGameOver
+	}
+
+	if len(g.control) > 0 {
+		color := g.board.Turn()
+		g.clocks[color] = max(g.remaining(g.now()), 0)
+	}
+	g.outcome = &outcome
+	g.drawOffer.pending = false
+	g.takeback.pending = false
+
+	return nil
+}
+
+// checkFlag ends the game if the side to move has run out of time at the moment.
+func (g *Game) checkFlag(now time.Time) bool {
+	if g.ended() || len(g.control) == 0 || g.remaining(now) > 0 {
+		return g.flagged
+	}
+
+	color := g.board.Turn()
+	g.flagged = true
+	g.flaggedColor = color
+	g.clocks[color] = 0
+
+	outcome := Outcome{
+		Result: winResult(!color),
+		Method: MethodTimeForfeit,
+		Reason: colorName(color) + " forfeits on time",
+	}
+	if !standardchess.CanCheckmate(g.board, !color) {
+		outcome = Outcome{
+			Result: pgn.ResultDraw,
+			Method: MethodTimeoutVsInsufficientMaterial,
+			Reason: colorName(color) + " ran out of time and " + colorName(!color) + " can't checkmate",
+		}
+	}
+	g.outcome = &outcome
+
+	return true
+}
+
+// remaining returns the remaining time of the side to move at the moment, it's negative after the flag fall.
+func (g *Game) remaining(now time.Time) time.Duration {
+	color := g.board.Turn()
+	stage, _ := g.control.stageAt(g.moves[color])
+	elapsed := now.Sub(g.turnStart)
+	if stage.Delay > 0 && stage.DelayType == DelaySimple {
+		elapsed = max(elapsed-stage.Delay, 0)
+	}
+
+	return g.clocks[color] - elapsed
+}
+
+// stopClock charges the player for the move by the stage of the time control
+// and returns the remaining time of the player.
+func (g *Game) stopClock(color chess.Color, elapsed time.Duration) time.Duration {
+	if len(g.control) == 0 {
+		return 0
+	}
+
+	stage, _ := g.control.stageAt(g.moves[color])
+	charged := elapsed
+	// Both delays charge the same time for the made move, they differ in the running clock only.
+	if stage.Delay > 0 {
+		charged -= min(elapsed, stage.Delay)
+	}
+	g.clocks[color] += stage.Increment - charged
+
+	if next, starts := g.control.stageAt(g.moves[color] + 1); starts {
+		g.clocks[color] += next.Time
+	}
+
+	return g.clocks[color]
+}
+
+// formatClock returns the duration in the format of the clock comments, e.g. "1:59:58" or "0:00:02.5".
+func formatClock(d time.Duration) string {
+	d = max(d, 0).Round(100 * time.Millisecond)
+	hours, minutes, seconds := int(d/time.Hour), int(d/time.Minute)%60, int(d/time.Second)%60
+	clock := fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	if tenths := int(d/(100*time.Millisecond)) % 10; tenths > 0 {
+		clock += fmt.Sprintf(".%d", tenths)
+	}
+
+	return clock
+}
@@ -0,0 +1,341 @@
+package game_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/elaxer/standardchess/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is the clock of the tests which is moved forward by hand.
+type fakeClock struct {
+	now time.Time
+}
+
+func TestGame_Move(t *testing.T) {
+	tests := []struct {
+		name    string
+		control game.TimeControl
+		// elapsed are the times spent on the moves e4, e5, Nf3.
+		elapsed   [3]time.Duration
+		wantWhite time.Duration
+		wantBlack time.Duration
+	}{
+		{
+			"sudden death", game.SuddenDeath(5 * time.Minute),
+			[3]time.Duration{10 * time.Second, 20 * time.Second, 5 * time.Second},
+			4*time.Minute + 45*time.Second, 4*time.Minute + 40*time.Second,
+		},
+		{
+			"fischer", game.Fischer(3*time.Minute, 2*time.Second),
+			[3]time.Duration{10 * time.Second, time.Second, 5 * time.Second},
+			2*time.Minute + 49*time.Second, 3*time.Minute + time.Second,
+		},
+		{
+			"bronstein", game.Bronstein(5*time.Minute, 5*time.Second),
+			[3]time.Duration{3 * time.Second, 8 * time.Second, 6 * time.Second},
+			4*time.Minute + 59*time.Second, 4*time.Minute + 57*time.Second,
+		},
+		{
+			"simple delay", game.SimpleDelay(5*time.Minute, 5*time.Second),
+			[3]time.Duration{3 * time.Second, 8 * time.Second, 6 * time.Second},
+			4*time.Minute + 59*time.Second, 4*time.Minute + 57*time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			g := game.New(standardchess.NewBoard(), tt.control, game.WithClock(clock.Now))
+
+			for i, move := range []string{"e4", "e5", "Nf3"} {
+				clock.advance(tt.elapsed[i])
+				_, err := g.Move(move)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantWhite, g.Remaining(chess.ColorWhite))
+			assert.Equal(t, tt.wantBlack, g.Remaining(chess.ColorBlack))
+
+			records := g.Records()
+			require.Len(t, records, 3)
+			assert.Equal(t, "Nf3", records[2].Move.String())
+			assert.Equal(t, tt.wantWhite, records[2].Clock)
+			assert.Equal(t, tt.elapsed[2], records[2].Elapsed)
+		})
+	}
+}
+
+func TestGame_Remaining_Delay(t *testing.T) {
+	tests := []struct {
+		name    string
+		control game.TimeControl
+		want    time.Duration
+	}{
+		{"bronstein", game.Bronstein(time.Minute, 5*time.Second), 57 * time.Second},
+		{"simple delay", game.SimpleDelay(time.Minute, 5*time.Second), time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			g := game.New(standardchess.NewBoard(), tt.control, game.WithClock(clock.Now))
+
+			clock.advance(3 * time.Second)
+			assert.Equal(t, tt.want, g.Remaining(chess.ColorWhite))
+			assert.Equal(t, time.Minute, g.Remaining(chess.ColorBlack))
+		})
+	}
+}
+
+func TestGame_Move_Stages(t *testing.T) {
+	tests := []struct {
+		name    string
+		control string
+		want    []time.Duration
+	}{
+		{"next stage", "2/60:30+5", []time.Duration{50 * time.Second, 70 * time.Second, 65 * time.Second}},
+		{"repeated stage", "2/60", []time.Duration{50 * time.Second, 100 * time.Second, 90 * time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			control, err := game.ParseTimeControl(tt.control)
+			require.NoError(t, err)
+
+			clock := newFakeClock()
+			g := game.New(standardchess.NewBoard(), control, game.WithClock(clock.Now))
+
+			var whiteClocks []time.Duration
+			for _, move := range []string{"e4", "e5", "Nf3", "Nc6", "Bb5"} {
+				clock.advance(10 * time.Second)
+				_, err := g.Move(move)
+				require.NoError(t, err)
+
+				if records := g.Records(); len(records)%2 == 1 {
+					whiteClocks = append(whiteClocks, records[len(records)-1].Clock)
+				}
+			}
+
+			assert.Equal(t, tt.want, whiteClocks)
+		})
+	}
+}
+
+func TestGame_Flag(t *testing.T) {
+	clock := newFakeClock()
+	g := game.New(standardchess.NewBoard(), game.SuddenDeath(time.Minute), game.WithClock(clock.Now))
+
+	clock.advance(30 * time.Second)
+	_, err := g.Move("e4")
+	require.NoError(t, err)
+
+	clock.advance(59 * time.Second)
+	_, flagged := g.Flag()
+	assert.False(t, flagged)
+	assert.Equal(t, pgn.ResultInProcess, g.Result())
+
+	clock.advance(time.Second)
+	color, flagged := g.Flag()
+	assert.True(t, flagged)
+	assert.Equal(t, chess.ColorBlack, color)
+	assert.True(t, g.IsOver())
+	assert.Equal(t, pgn.ResultWinWhite, g.Result())
+	assert.Equal(t, time.Duration(0), g.Remaining(chess.ColorBlack))
+
+	_, err = g.Move("e5")
+	assert.ErrorIs(t, err, game.ErrFlagFell)
+}
+
+func TestGame_Move_FlagFell(t *testing.T) {
+	clock := newFakeClock()
+	g := game.New(standardchess.NewBoard(), game.SimpleDelay(time.Minute, 5*time.Second), game.WithClock(clock.Now))
+
+	clock.advance(time.Minute + 5*time.Second)
+	_, err := g.Move("e4")
+	assert.ErrorIs(t, err, game.ErrFlagFell)
+	assert.Empty(t, g.Board().MoveHistory())
+	assert.Equal(t, pgn.ResultWinBlack, g.Result())
+}
+
+func TestGame_Result_InsufficientMaterial(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		want pgn.Result
+	}{
+		{"opponent has a lone king", "8/8/3k4/8/8/4K3/3R4/8 w - - 0 1", pgn.ResultDraw},
+		{"opponent has a rook", "8/8/3k4/8/8/4K3/3R4/8 b - - 0 1", pgn.ResultWinWhite},
+		{"opponent has a knight against a pawn", "8/8/3k4/3n4/8/4K3/3P4/8 w - - 0 1", pgn.ResultWinBlack},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := fen.Decode(tt.fen)
+			require.NoError(t, err)
+
+			clock := newFakeClock()
+			g := game.New(board, game.SuddenDeath(time.Second), game.WithClock(clock.Now))
+			clock.advance(time.Second)
+
+			assert.Equal(t, tt.want, g.Result())
+		})
+	}
+}
+
+func TestGame_Untimed(t *testing.T) {
+	clock := newFakeClock()
+	g := game.New(standardchess.NewBoard(), nil, game.WithClock(clock.Now))
+
+	clock.advance(time.Hour)
+	_, err := g.Move("e4")
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), g.Remaining(chess.ColorWhite))
+	assert.False(t, g.IsOver())
+
+	p := g.PGN(nil)
+	_, ok := p.Headers().Get(pgn.HeaderTimeControl)
+	assert.False(t, ok)
+}
+
+func TestGame_Move_Checkmate(t *testing.T) {
+	clock := newFakeClock()
+	g := game.New(standardchess.NewBoard(), game.SuddenDeath(time.Minute), game.WithClock(clock.Now))
+
+	for _, move := range []string{"f3", "e5", "g4", "Qh4#"} {
+		_, err := g.Move(move)
+		require.NoError(t, err)
+	}
+
+	clock.advance(time.Hour)
+	assert.True(t, g.IsOver())
+	_, flagged := g.Flag()
+	assert.False(t, flagged)
+	assert.Equal(t, pgn.ResultWinBlack, g.Result())
+	assert.Equal(t, time.Minute, g.Remaining(chess.ColorWhite))
+
+	_, err := g.Move("a3")
+	assert.ErrorIs(t, err, game.ErrGameOver)
+}
+
+func TestGame_Outcome(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		end     func(g *game.Game) error
+		want    game.Outcome
+	}{
+		{
+			"resignation", 59 * time.Second, func(g *game.Game) error { return g.Resign(chess.ColorWhite) },
+			game.Outcome{Result: pgn.ResultWinBlack, Method: game.MethodResignation, Reason: "White resigns"},
+		},
+		{
+			"abandonment", 59 * time.Second, func(g *game.Game) error { return g.Abandon(chess.ColorBlack) },
+			game.Outcome{Result: pgn.ResultWinWhite, Method: game.MethodAbandonment, Reason: "Black abandons"},
+		},
+		{
+			"forfeit", 59 * time.Second, func(g *game.Game) error { return g.Forfeit(chess.ColorBlack, "Black didn't show up") },
+			game.Outcome{Result: pgn.ResultWinWhite, Method: game.MethodForfeit, Reason: "Black didn't show up"},
+		},
+		{
+			"adjudication", 59 * time.Second, func(g *game.Game) error { return g.Adjudicate(pgn.ResultDraw, "Dead position") },
+			game.Outcome{Result: pgn.ResultDraw, Method: game.MethodAdjudication, Reason: "Dead position"},
+		},
+		{
+			"time forfeit", time.Minute, func(*game.Game) error { return nil },
+			game.Outcome{Result: pgn.ResultWinWhite, Method: game.MethodTimeForfeit, Reason: "Black forfeits on time"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			g := game.New(standardchess.NewBoard(), game.SuddenDeath(time.Minute), game.WithClock(clock.Now))
+			makeMoves(t, g, "e4")
+
+			_, over := g.Outcome()
+			assert.False(t, over)
+
+			clock.advance(tt.elapsed)
+			require.NoError(t, tt.end(g))
+
+			outcome, over := g.Outcome()
+			assert.True(t, over)
+			assert.Equal(t, tt.want, outcome)
+			assert.Equal(t, tt.want.Result, g.Result())
+			assert.Equal(t, time.Minute-tt.elapsed, g.Remaining(chess.ColorBlack))
+
+			assert.ErrorIs(t, g.Resign(chess.ColorBlack), game.ErrGameOver)
+			clock.advance(time.Hour)
+			outcome, _ = g.Outcome()
+			assert.Equal(t, tt.want, outcome)
+		})
+	}
+}
+
+func TestGame_Adjudicate_InvalidResult(t *testing.T) {
+	g := game.New(standardchess.NewBoard(), nil)
+
+	assert.ErrorIs(t, g.Adjudicate(pgn.ResultInProcess, ""), game.ErrInvalidResult)
+	assert.False(t, g.IsOver())
+}
+
+func TestGame_PGN_Termination(t *testing.T) {
+	g := game.New(standardchess.NewBoard(), nil)
+	makeMoves(t, g, "e4")
+
+	p := g.PGN(nil)
+	_, ok := p.Headers().Get(pgn.HeaderTermination)
+	assert.False(t, ok)
+
+	require.NoError(t, g.Abandon(chess.ColorBlack))
+	p = g.PGN(nil)
+	assert.Equal(t, pgn.TerminationAbandoned, p.Headers().Value(pgn.HeaderTermination))
+	assert.Equal(t, pgn.ResultWinWhite, p.Result())
+	assert.Equal(t, "unknown", g.PGN(pgn.Headers{pgn.NewHeader(pgn.HeaderTermination, "unknown")}).Headers().Value(
+		pgn.HeaderTermination))
+}
+
+func TestGame_PGN(t *testing.T) {
+	clock := newFakeClock()
+	g := game.New(standardchess.NewBoard(), game.Fischer(5*time.Minute, 3*time.Second), game.WithClock(clock.Now))
+
+	for _, step := range []struct {
+		move    string
+		elapsed time.Duration
+	}{
+		{"e4", 2 * time.Second},
+		{"c5", 2500 * time.Millisecond},
+		{"Nf3", 75 * time.Second},
+	} {
+		clock.advance(step.elapsed)
+		_, err := g.Move(step.move)
+		require.NoError(t, err)
+	}
+
+	p := g.PGN(pgn.Headers{pgn.NewHeader(pgn.HeaderEvent, "Blitz")})
+
+	header, ok := p.Headers().Get(pgn.HeaderTimeControl)
+	require.True(t, ok)
+	assert.Equal(t, "300+3", header.Value)
+
+	mainline := p.Root().Mainline()
+	require.Len(t, mainline, 3)
+	assert.Equal(t, []string{"[%clk 0:05:01] [%emt 0:00:02]"}, mainline[0].Comments)
+	assert.Equal(t, []string{"[%clk 0:05:00.5] [%emt 0:00:02.5]"}, mainline[1].Comments)
+	assert.Equal(t, []string{"[%clk 0:03:49] [%emt 0:01:15]"}, mainline[2].Comments)
+	assert.Equal(t, pgn.ResultInProcess, p.Result())
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/pgn"
+)
+
+// The methods by which a game ends.
+const (
+	MethodCheckmate Method = iota + 1
+	MethodStalemate
+	MethodInsufficientMaterial
+	MethodFiftyMoves
+	MethodThreefoldRepetition
+	// MethodTimeForfeit means a player has run out of time and lost the game.
+	MethodTimeForfeit
+	// MethodTimeoutVsInsufficientMaterial means a player has run out of time
+	// and the game is drawn because the opponent can't checkmate.
+	MethodTimeoutVsInsufficientMaterial
+	MethodResignation
+	// MethodAgreement means the players have agreed to a draw.
+	MethodAgreement
+	// MethodForfeit means a player has lost the game by a rules infraction, e.g. not showing up.
+	MethodForfeit
+	MethodAbandonment
+	// MethodAdjudication means the result has been decided by an arbiter or a program.
+	MethodAdjudication
+)
+
+var methodNames = map[Method]string{
+	MethodCheckmate:                     "checkmate",
+	MethodStalemate:                     "stalemate",
+	MethodInsufficientMaterial:          "insufficient material",
+	MethodFiftyMoves:                    "fifty moves rule",
+	MethodThreefoldRepetition:           "threefold repetition",
+	MethodTimeForfeit:                   "time forfeit",
+	MethodTimeoutVsInsufficientMaterial: "timeout vs insufficient material",
+	MethodResignation:                   "resignation",
+	MethodAgreement:                     "agreement",
+	MethodForfeit:                       "forfeit",
+	MethodAbandonment:                   "abandonment",
+	MethodAdjudication:                  "adjudication",
+}
+
+// Method is the way a game has ended.
+type Method uint8
+
+// Outcome is the outcome of a finished game.
+type Outcome struct {
+	Result pgn.Result
+	Method Method
+	// Reason describes the outcome for the players, e.g. "White mates" or "Black resigns".
+	Reason string
+}
+
+// OutcomeFromBoard returns the outcome of the game finished on the board.
+// The second value is false if the board isn't in a terminal state.
+func OutcomeFromBoard(board chess.Board) (Outcome, bool) {
+	result := pgn.ResultFromBoard(board)
+	if result.IsInProcess() {
+		return Outcome{}, false
+	}
+
+	switch board.State() {
+	case standardchess.StateCheckmate:
+		return Outcome{Result: result, Method: MethodCheckmate, Reason: colorName(!board.Turn()) + " mates"}, true
+	case standardchess.StateStalemate:
+		return Outcome{Result: result, Method: MethodStalemate, Reason: "Stalemate"}, true
+	case standardchess.StateFiftyMoves:
+		return Outcome{Result: result, Method: MethodFiftyMoves, Reason: "50 move rule"}, true
+	case standardchess.StateThreefoldRepetition:
+		return Outcome{Result: result, Method: MethodThreefoldRepetition, Reason: "Draw by repetition"}, true
+	default:
+		return Outcome{Result: result, Method: MethodInsufficientMaterial, Reason: "Insufficient material"}, true
+	}
+}
+
+// Winner returns the color of the winner, the second value is false if the game is drawn.
+func (o Outcome) Winner() (chess.Color, bool) {
+	switch o.Result {
+	case pgn.ResultWinWhite:
+		return chess.ColorWhite, true
+	case pgn.ResultWinBlack:
+		return chess.ColorBlack, true
+	default:
+		return chess.ColorWhite, false
+	}
+}
+
+// IsDraw reports whether the game is drawn.
+func (o Outcome) IsDraw() bool {
+	return o.Result == pgn.ResultDraw
+}
+
+// Termination returns the value of the PGN Termination header of the outcome, e.g. "time forfeit".
+func (o Outcome) Termination() string {
+	switch o.Method {
+	case MethodTimeForfeit, MethodTimeoutVsInsufficientMaterial:
+		return pgn.TerminationTimeForfeit
+	case MethodForfeit:
+		return pgn.TerminationRulesInfraction
+	case MethodAbandonment:
+		return pgn.TerminationAbandoned
+	case MethodAdjudication:
+		return pgn.TerminationAdjudication
+	default:
+		return pgn.TerminationNormal
+	}
+}
+
+func (m Method) String() string {
+	return methodNames[m]
+}
+
+// colorName returns the name of the color in the reasons of the outcomes, "White" or "Black".
+func colorName(color chess.Color) string {
+	if color.IsWhite() {
+		return "White"
+	}
+
+	return "Black"
+}
+
+// winResult returns the result of the game won by the color.
+func winResult(color chess.Color) pgn.Result {
+	if color.IsWhite() {
+		return pgn.ResultWinWhite
+	}
+
+	return pgn.ResultWinBlack
+}
@@ -0,0 +1,91 @@
+package game_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/elaxer/standardchess/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutcomeFromBoard(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		moves []string
+		want  game.Outcome
+	}{
+		{
+			"checkmate", "", []string{"f3", "e5", "g4", "Qh4#"},
+			game.Outcome{Result: pgn.ResultWinBlack, Method: game.MethodCheckmate, Reason: "Black mates"},
+		},
+		{
+			"stalemate", "7k/5Q2/6K1/8/8/8/8/8 b - - 0 1", nil,
+			game.Outcome{Result: pgn.ResultDraw, Method: game.MethodStalemate, Reason: "Stalemate"},
+		},
+		{
+			"threefold repetition", "", []string{"Nf3", "Nf6", "Ng1", "Ng8", "Nf3", "Nf6", "Ng1", "Ng8"},
+			game.Outcome{Result: pgn.ResultDraw, Method: game.MethodThreefoldRepetition, Reason: "Draw by repetition"},
+		},
+		{
+			"insufficient material", "8/8/4k3/8/8/4K3/8/8 w - - 0 1", nil,
+			game.Outcome{Result: pgn.ResultDraw, Method: game.MethodInsufficientMaterial, Reason: "Insufficient material"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := standardchess.NewBoard()
+			if tt.fen != "" {
+				var err error
+				board, err = fen.Decode(tt.fen)
+				require.NoError(t, err)
+			}
+			for _, move := range tt.moves {
+				_, err := board.MakeMove(move)
+				require.NoError(t, err)
+			}
+
+			outcome, ok := game.OutcomeFromBoard(board)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, outcome)
+		})
+	}
+
+	_, ok := game.OutcomeFromBoard(standardchess.NewBoard())
+	assert.False(t, ok)
+}
+
+func TestOutcome_Winner(t *testing.T) {
+	winner, ok := game.Outcome{Result: pgn.ResultWinBlack}.Winner()
+	assert.True(t, ok)
+	assert.Equal(t, chess.ColorBlack, winner)
+
+	_, ok = game.Outcome{Result: pgn.ResultDraw}.Winner()
+	assert.False(t, ok)
+	assert.True(t, game.Outcome{Result: pgn.ResultDraw}.IsDraw())
+}
+
+func TestOutcome_Termination(t *testing.T) {
+	tests := []struct {
+		method game.Method
+		want   string
+	}{
+		{game.MethodCheckmate, pgn.TerminationNormal},
+		{game.MethodAgreement, pgn.TerminationNormal},
+		{game.MethodResignation, pgn.TerminationNormal},
+		{game.MethodTimeForfeit, pgn.TerminationTimeForfeit},
+		{game.MethodTimeoutVsInsufficientMaterial, pgn.TerminationTimeForfeit},
+		{game.MethodForfeit, pgn.TerminationRulesInfraction},
+		{game.MethodAbandonment, pgn.TerminationAbandoned},
+		{game.MethodAdjudication, pgn.TerminationAdjudication},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, game.Outcome{Method: tt.method}.Termination())
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package game
+
+import (
+	"errors"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/encoding/pgn"
+)
+
+var (
+	ErrNoRequest      = errors.New("there is no request of the opponent")
+	ErrRequestPending = errors.New("the request is pending")
+	ErrRequestLimit   = errors.New("one request of a kind per turn is allowed")
+	ErrNoTakeback     = errors.New("there is no move to take back")
+)
+
+// request is a draw offer or a takeback request of a player.
+type request struct {
+	pending bool
+	color   chess.Color
+	// turns are the turns of the players at their last requests, see Game.turn.
+	turns map[chess.Color]int
+}
+
+// OfferDraw offers a draw to the opponent of the player, the draw is agreed at once if the opponent has offered it.
+// The offer stands until the opponent accepts or declines it or makes a move.
+// A player may offer a draw once per turn.
+func (g *Game) OfferDraw(color chess.Color) error {
+	if g.IsOver() {
+		return ErrGameOver
+	}
+	if g.drawOffer.pending && g.drawOffer.color != color {
+		return g.AcceptDraw(color)
+	}
+
+	return g.drawOffer.make(color, g.turn(color))
+}
+
+// AcceptDraw ends the game by the agreement to the draw offered by the opponent of the player.
+func (g *Game) AcceptDraw(color chess.Color) error {
+	if g.IsOver() {
+		return ErrGameOver
+	}
+	if err := g.drawOffer.answer(color); err != nil {
+		return err
+	}
+
+	return g.end(Outcome{Result: pgn.ResultDraw, Method: MethodAgreement, Reason: "Draw agreed"})
+}
+
+// DeclineDraw declines the draw offered by the opponent of the player.
+func (g *Game) DeclineDraw(color chess.Color) error {
+	if g.IsOver() {
+		return ErrGameOver
+	}
+
+	return g.drawOffer.answer(color)
+}
+
+// DrawOffer returns the color of the player who has offered a draw, the second value is false if there is no offer.
+func (g *Game) DrawOffer() (chess.Color, bool) {
+	return g.drawOffer.color, g.drawOffer.pending
+}
+
+// RequestTakeback asks the opponent to take back the last move of the player and the reply to it if it's made.
+// The request stands until the opponent accepts or declines it or a move is made.
+// A player may request a takeback once per turn.
+func (g *Game) RequestTakeback(color chess.Color) error {
+	if g.IsOver() {
+		return ErrGameOver
+	}
+	if g.moves[color] == 0 {
+		return ErrNoTakeback
+	}
+
+	return g.takeback.make(color, g.turn(color))
+}
+
+// AcceptTakeback takes back the moves requested by the opponent of the player.
+// The clocks are set back to the times before the moves.
+func (g *Game) AcceptTakeback(color chess.Color) error {
+	if g.IsOver() {
+		return ErrGameOver
+	}
+	if err := g.takeback.answer(color); err != nil {
+		return err
+	}
+
+	plies := 1
+	if g.board.Turn() != color {
+		plies = 2
+	}
+	for range plies {
+		if err := g.undo(); err != nil {
+			return err
+		}
+	}
+	g.turnStart = g.now()
+	g.drawOffer.pending = false
+
+	return nil
+}
+
+// DeclineTakeback declines the takeback requested by the opponent of the player.
+func (g *Game) DeclineTakeback(color chess.Color) error {
+	if g.IsOver() {
+		return ErrGameOver
+	}
+
+	return g.takeback.answer(color)
+}
+
+// TakebackRequest returns the color of the player who has requested a takeback,
+// the second value is false if there is no request.
+func (g *Game) TakebackRequest() (chess.Color, bool) {
+	return g.takeback.color, g.takeback.pending
+}
+
+// turn returns the number of the turn of the player. The turn lasts from a move of the opponent
+// to the next one, so the player's requests before and after its move are made in the same turn.
+func (g *Game) turn(color chess.Color) int {
+	if g.board.Turn() == color {
+		return g.moves[color]
+	}
+
+	return g.moves[color] - 1
+}
+
+// undo takes back the last move of the game and sets the clock of the player back.
+func (g *Game) undo() error {
+	color := !g.board.Turn()
+	if _, err := g.board.UndoLastMove(); err != nil {
+		return err
+	}
+
+	g.records = g.records[:len(g.records)-1]
+	g.moves[color]--
+	if len(g.control) > 0 {
+		// The moves of the players alternate, so the previous move of the player is the second to last one.
+		g.clocks[color] = g.control[0].Time
+		if n := len(g.records); n >= 2 {
+			g.clocks[color] = g.records[n-2].Clock
+		}
+	}
+
+	return nil
+}
+
+func (r *request) make(color chess.Color, turn int) error {
+	if r.pending {
+		return ErrRequestPending
+	}
+	if last, ok := r.turns[color]; ok && last == turn {
+		return ErrRequestLimit
+	}
+
+	if r.turns == nil {
+		r.turns = make(map[chess.Color]int, 2)
+	}
+	r.pending, r.color, r.turns[color] = true, color, turn
+
+	return nil
+}
+
+func (r *request) answer(color chess.Color) error {
+	if !r.pending || r.color == color {
+		return ErrNoRequest
+	}
+	r.pending = false
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package game_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/elaxer/standardchess/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_AcceptDraw(t *testing.T) {
+	g := game.New(standardchess.NewBoard(), nil)
+	makeMoves(t, g, "e4")
+
+	require.NoError(t, g.OfferDraw(chess.ColorWhite))
+	color, ok := g.DrawOffer()
+	assert.True(t, ok)
+	assert.Equal(t, chess.ColorWhite, color)
+
+	assert.ErrorIs(t, g.AcceptDraw(chess.ColorWhite), game.ErrNoRequest)
+	require.NoError(t, g.AcceptDraw(chess.ColorBlack))
+
+	outcome, over := g.Outcome()
+	assert.True(t, over)
+	assert.Equal(t, game.Outcome{Result: pgn.ResultDraw, Method: game.MethodAgreement, Reason: "Draw agreed"}, outcome)
+	_, ok = g.DrawOffer()
+	assert.False(t, ok)
+
+	_, err := g.Move("e5")
+	assert.ErrorIs(t, err, game.ErrGameOver)
+	assert.ErrorIs(t, g.OfferDraw(chess.ColorBlack), game.ErrGameOver)
+}
+
+func TestGame_OfferDraw(t *testing.T) {
+	g := game.New(standardchess.NewBoard(), nil)
+
+	// The offer made before the move stands after it and counts for the turn.
+	require.NoError(t, g.OfferDraw(chess.ColorWhite))
+	makeMoves(t, g, "e4")
+	_, ok := g.DrawOffer()
+	assert.True(t, ok)
+	require.NoError(t, g.DeclineDraw(chess.ColorBlack))
+	assert.ErrorIs(t, g.OfferDraw(chess.ColorWhite), game.ErrRequestLimit)
+	assert.ErrorIs(t, g.DeclineDraw(chess.ColorBlack), game.ErrNoRequest)
+
+	// The move of the opponent declines the offer.
+	require.NoError(t, g.OfferDraw(chess.ColorBlack))
+	assert.ErrorIs(t, g.OfferDraw(chess.ColorBlack), game.ErrRequestPending)
+	makeMoves(t, g, "e5", "Nf3")
+	_, ok = g.DrawOffer()
+	assert.False(t, ok)
+	assert.ErrorIs(t, g.AcceptDraw(chess.ColorBlack), game.ErrNoRequest)
+
+	// The offers of both players agree to a draw.
+	require.NoError(t, g.OfferDraw(chess.ColorWhite))
+	require.NoError(t, g.OfferDraw(chess.ColorBlack))
+	assert.Equal(t, pgn.ResultDraw, g.Result())
+}
+
+func TestGame_AcceptTakeback(t *testing.T) {
+	tests := []struct {
+		name      string
+		requester chess.Color
+		wantMoves int
+		wantWhite time.Duration
+		wantBlack time.Duration
+	}{
+		{"opponent to move", chess.ColorWhite, 2, 4*time.Minute + 50*time.Second, 4*time.Minute + 50*time.Second},
+		{"requester to move", chess.ColorBlack, 1, 4*time.Minute + 50*time.Second, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			g := game.New(standardchess.NewBoard(), game.SuddenDeath(5*time.Minute), game.WithClock(clock.Now))
+			for _, move := range []string{"e4", "e5", "Nf3"} {
+				clock.advance(10 * time.Second)
+				makeMoves(t, g, move)
+			}
+
+			require.NoError(t, g.RequestTakeback(tt.requester))
+			color, ok := g.TakebackRequest()
+			assert.True(t, ok)
+			assert.Equal(t, tt.requester, color)
+			assert.ErrorIs(t, g.AcceptTakeback(tt.requester), game.ErrNoRequest)
+
+			clock.advance(10 * time.Second)
+			require.NoError(t, g.AcceptTakeback(!tt.requester))
+
+			assert.Len(t, g.Board().MoveHistory(), tt.wantMoves)
+			assert.Len(t, g.Records(), tt.wantMoves)
+			assert.Equal(t, tt.requester, g.Board().Turn())
+			assert.Equal(t, tt.wantWhite, g.Remaining(chess.ColorWhite))
+			assert.Equal(t, tt.wantBlack, g.Remaining(chess.ColorBlack))
+			_, ok = g.TakebackRequest()
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestGame_RequestTakeback(t *testing.T) {
+	g := game.New(standardchess.NewBoard(), nil)
+
+	assert.ErrorIs(t, g.RequestTakeback(chess.ColorWhite), game.ErrNoTakeback)
+	makeMoves(t, g, "e4")
+	assert.ErrorIs(t, g.RequestTakeback(chess.ColorBlack), game.ErrNoTakeback)
+
+	require.NoError(t, g.RequestTakeback(chess.ColorWhite))
+	require.NoError(t, g.DeclineTakeback(chess.ColorBlack))
+	assert.ErrorIs(t, g.RequestTakeback(chess.ColorWhite), game.ErrRequestLimit)
+
+	// The request expires with the next move.
+	makeMoves(t, g, "e5")
+	require.NoError(t, g.RequestTakeback(chess.ColorBlack))
+	makeMoves(t, g, "Nf3")
+	_, ok := g.TakebackRequest()
+	assert.False(t, ok)
+	assert.ErrorIs(t, g.AcceptTakeback(chess.ColorWhite), game.ErrNoRequest)
+	assert.Len(t, g.Board().MoveHistory(), 3)
+}
+
+func makeMoves(t *testing.T, g *game.Game, moves ...string) {
+	t.Helper()
+
+	for _, move := range moves {
+		_, err := g.Move(move)
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,182 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The types of the delay of a stage.
+const (
+	// DelaySimple doesn't run the clock for the delay at the start of each move, also known as the US delay.
+	DelaySimple DelayType = iota
+	// DelayBronstein adds the time spent on the move back to the clock after the move, up to the delay.
+	DelayBronstein
+)
+
+// DelayType is the way the delay of a stage is applied.
+type DelayType uint8
+
+// Stage is a stage of a time control.
+type Stage struct {
+	// Moves is the number of the moves of each player in the stage,
+	// zero if the stage lasts until the end of the game.
+	Moves int
+	// Time is the time added to the clock at the start of the stage.
+	Time time.Duration
+	// Increment is the time added to the clock after each move, also known as the Fischer increment.
+	Increment time.Duration
+	// Delay is the delay of each move applied by DelayType.
+	Delay     time.Duration
+	DelayType DelayType
+}
+
+// TimeControl is the sequence of the stages of a game, e.g. 40 moves in 2 hours followed by 30 minutes
+// with 30 seconds increment until the end of the game. The last stage is repeated if it has a number of moves.
+type TimeControl []Stage
+
+// SuddenDeath returns the time control with the time for the whole game.
+func SuddenDeath(t time.Duration) TimeControl {
+	return TimeControl{{Time: t}}
+}
+
+// Fischer returns the time control with the time for the whole game and the increment after each move.
+func Fischer(t, increment time.Duration) TimeControl {
+	return TimeControl{{Time: t, Increment: increment}}
+}
+
+// Bronstein returns the time control with the time for the whole game and the Bronstein delay of each move.
+func Bronstein(t, delay time.Duration) TimeControl {
+	return TimeControl{{Time: t, Delay: delay, DelayType: DelayBronstein}}
+}
+
+// SimpleDelay returns the time control with the time for the whole game and the simple delay of each move.
+func SimpleDelay(t, delay time.Duration) TimeControl {
+	return TimeControl{{Time: t, Delay: delay, DelayType: DelaySimple}}
+}
+
+// Classical returns the multi-stage time control of the classical games: the time for the moves
+// followed by the time for the rest of the game, the increment is added after each move from the first one.
+// E.g. Classical(40, 2*time.Hour, 30*time.Minute, 0) is the control written as 40/120+30 in minutes,
+// and the FIDE one is Classical(40, 90*time.Minute, 30*time.Minute, 30*time.Second).
+func Classical(moves int, t, rest, increment time.Duration) TimeControl {
+	return TimeControl{
+		{Moves: moves, Time: t, Increment: increment},
+		{Time: rest, Increment: increment},
+	}
+}
+
+// ParseTimeControl parses the value of the PGN TimeControl header: the stages separated by colons,
+// each of them is the number of the moves and the seconds, e.g. "40/7200", the seconds, e.g. "300",
+// or the seconds and the increment, e.g. "180+2". So "40/7200:1800+30" is 40 moves in 2 hours
+// followed by 30 minutes with 30 seconds increment. The times are in seconds and an increment belongs
+// to its stage, so "40/120+30" is read as 40 moves in 2 minutes with 30 seconds increment.
+// The classical 40 moves in 120 minutes followed by 30 minutes for the rest of the game is "40/7200:1800",
+// see Classical.
+// "-", the game isn't timed, gives an empty time control. ErrUnknownTimeControl is returned for "?"
+// and ErrSandclockTimeControl for the sandclock controls, e.g. "*180".
+func ParseTimeControl(s string) (TimeControl, error) {
+	switch {
+	case s == "-":
+		return TimeControl{}, nil
+	case s == "?":
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTimeControl, ErrUnknownTimeControl)
+	case strings.HasPrefix(s, "*"):
+		return nil, fmt.Errorf("%w: %w: %s", ErrInvalidTimeControl, ErrSandclockTimeControl, s)
+	}
+
+	fields := strings.Split(s, ":")
+	tc := make(TimeControl, 0, len(fields))
+	for i, field := range fields {
+		stage, err := parseStage(field)
+		if err != nil {
+			return nil, err
+		}
+		if stage.Moves == 0 && i < len(fields)-1 {
+			return nil, fmt.Errorf("%w: %s: only the last stage lasts until the end of the game", ErrInvalidTimeControl, s)
+		}
+
+		tc = append(tc, stage)
+	}
+
+	return tc, nil
+}
+
+// String returns the time control in the format of the PGN TimeControl header, see ParseTimeControl.
+// The delays can't be written in the format and are omitted. An empty time control is "-".
+func (tc TimeControl) String() string {
+	if len(tc) == 0 {
+		return "-"
+	}
+
+	fields := make([]string, 0, len(tc))
+	for _, stage := range tc {
+		field := formatSeconds(stage.Time)
+		if stage.Moves > 0 {
+			field = strconv.Itoa(stage.Moves) + "/" + field
+		}
+		if stage.Increment > 0 {
+			field += "+" + formatSeconds(stage.Increment)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return strings.Join(fields, ":")
+}
+
+// stageAt returns the stage of the move of a player with the number of the moves made by the player before it.
+// The second value reports whether the stage starts with the move.
+func (tc TimeControl) stageAt(moves int) (Stage, bool) {
+	start := 0
+	for _, stage := range tc {
+		if stage.Moves == 0 || moves < start+stage.Moves {
+			return stage, moves == start
+		}
+		start += stage.Moves
+	}
+
+	last := tc[len(tc)-1]
+
+	return last, (moves-start)%last.Moves == 0
+}
+
+func parseStage(field string) (Stage, error) {
+	var stage Stage
+
+	timeField := field
+	if movesField, rest, ok := strings.Cut(field, "/"); ok {
+		moves, err := strconv.Atoi(movesField)
+		if err != nil || moves <= 0 {
+			return Stage{}, fmt.Errorf("%w: %s", ErrInvalidTimeControl, field)
+		}
+		stage.Moves, timeField = moves, rest
+	}
+
+	timeField, incrementField, hasIncrement := strings.Cut(timeField, "+")
+	var err error
+	if stage.Time, err = parseSeconds(timeField); err != nil || stage.Time <= 0 {
+		return Stage{}, fmt.Errorf("%w: %s", ErrInvalidTimeControl, field)
+	}
+	if hasIncrement {
+		if stage.Increment, err = parseSeconds(incrementField); err != nil {
+			return Stage{}, fmt.Errorf("%w: %s", ErrInvalidTimeControl, field)
+		}
+	}
+
+	return stage, nil
+}
+
+func parseSeconds(s string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidTimeControl, s)
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
@@ -0,0 +1,91 @@
+package game_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elaxer/standardchess/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeControl(t *testing.T) {
+	tests := []struct {
+		s    string
+		want game.TimeControl
+	}{
+		{"300", game.SuddenDeath(5 * time.Minute)},
+		{"180+2", game.Fischer(3*time.Minute, 2*time.Second)},
+		{"0.5+0.1", game.Fischer(500*time.Millisecond, 100*time.Millisecond)},
+		{"40/7200:1800+30", game.TimeControl{
+			{Moves: 40, Time: 2 * time.Hour},
+			{Time: 30 * time.Minute, Increment: 30 * time.Second},
+		}},
+		{"40/5400:20/1800:900", game.TimeControl{
+			{Moves: 40, Time: 90 * time.Minute},
+			{Moves: 20, Time: 30 * time.Minute},
+			{Time: 15 * time.Minute},
+		}},
+		{"40/300", game.TimeControl{{Moves: 40, Time: 5 * time.Minute}}},
+		{"40/120+30", game.TimeControl{{Moves: 40, Time: 2 * time.Minute, Increment: 30 * time.Second}}},
+		{"-", game.TimeControl{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			tc, err := game.ParseTimeControl(tt.s)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tc)
+			assert.Equal(t, tt.s, tc.String())
+		})
+	}
+}
+
+func TestParseTimeControl_Invalid(t *testing.T) {
+	for _, s := range []string{"", "0", "-60", "40/", "/300", "0/300", "x/300", "300+", "300+x", "300:40/60"} {
+		t.Run(s, func(t *testing.T) {
+			_, err := game.ParseTimeControl(s)
+			assert.ErrorIs(t, err, game.ErrInvalidTimeControl)
+		})
+	}
+}
+
+func TestParseTimeControl_Unsupported(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"?", game.ErrUnknownTimeControl},
+		{"*180", game.ErrSandclockTimeControl},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			_, err := game.ParseTimeControl(tt.s)
+			assert.ErrorIs(t, err, tt.err)
+			assert.ErrorIs(t, err, game.ErrInvalidTimeControl)
+		})
+	}
+}
+
+func TestClassical(t *testing.T) {
+	tests := []struct {
+		name string
+		tc   game.TimeControl
+		want string
+	}{
+		{"40/120+30", game.Classical(40, 2*time.Hour, 30*time.Minute, 0), "40/7200:1800"},
+		{"fide", game.Classical(40, 90*time.Minute, 30*time.Minute, 30*time.Second), "40/5400+30:1800+30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.tc.String())
+
+			tc, err := game.ParseTimeControl(tt.want)
+			require.NoError(t, err)
+			assert.Equal(t, tt.tc, tc)
+		})
+	}
+}
+
+func TestTimeControl_String_Delay(t *testing.T) {
+	assert.Equal(t, "300", game.Bronstein(5*time.Minute, 3*time.Second).String())
+}
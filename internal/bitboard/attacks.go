@@ -0,0 +1,115 @@
+package bitboard
+
+import "math/bits"
+
+const (
+	pawn = iota
+	knight
+	bishop
+	rook
+	queen
+	king
+
+	pieceTypes
+)
+
+const (
+	north = iota
+	east
+	northEast
+	northWest
+	south
+	west
+	southWest
+	southEast
+
+	directions
+)
+
+var (
+	knightAttacks [64]Bitboard
+	kingAttacks   [64]Bitboard
+	// pawnAttacks contains the squares attacked by a pawn of the color (white, black) from the square.
+	pawnAttacks [2][64]Bitboard
+	// rays contains the squares in the direction from the square to the edge of the board.
+	rays [directions][64]Bitboard
+)
+
+var directionSteps = [directions][2]int{
+	north:     {0, 1},
+	east:      {1, 0},
+	northEast: {1, 1},
+	northWest: {-1, 1},
+	south:     {0, -1},
+	west:      {-1, 0},
+	southWest: {-1, -1},
+	southEast: {1, -1},
+}
+
+func init() {
+	knightSteps := [...][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+	kingSteps := [...][2]int{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}}
+
+	for square := range Square(64) {
+		for _, step := range knightSteps {
+			knightAttacks[square] |= target(square, step[0], step[1])
+		}
+		for _, step := range kingSteps {
+			kingAttacks[square] |= target(square, step[0], step[1])
+		}
+
+		pawnAttacks[0][square] = target(square, -1, 1) | target(square, 1, 1)
+		pawnAttacks[1][square] = target(square, -1, -1) | target(square, 1, -1)
+
+		for direction, step := range directionSteps {
+			for distance := 1; distance < 8; distance++ {
+				rays[direction][square] |= target(square, step[0]*distance, step[1]*distance)
+			}
+		}
+	}
+}
+
+// BishopAttacks returns the squares attacked by a bishop from the square with the occupied squares.
+func BishopAttacks(square Square, occupied Bitboard) Bitboard {
+	return slidingAttacks(square, occupied, northEast) |
+		slidingAttacks(square, occupied, northWest) |
+		slidingAttacks(square, occupied, southWest) |
+		slidingAttacks(square, occupied, southEast)
+}
+
+// RookAttacks returns the squares attacked by a rook from the square with the occupied squares.
+func RookAttacks(square Square, occupied Bitboard) Bitboard {
+	return slidingAttacks(square, occupied, north) |
+		slidingAttacks(square, occupied, east) |
+		slidingAttacks(square, occupied, south) |
+		slidingAttacks(square, occupied, west)
+}
+
+// slidingAttacks returns the ray in the direction cut off behind the first occupied square.
+func slidingAttacks(square Square, occupied Bitboard, direction int) Bitboard {
+	ray := rays[direction][square]
+
+	blockers := ray & occupied
+	if blockers == 0 {
+		return ray
+	}
+
+	var blocker Square
+	if direction < south {
+		blocker = Square(bits.TrailingZeros64(uint64(blockers)))
+	} else {
+		blocker = Square(63 - bits.LeadingZeros64(uint64(blockers)))
+	}
+
+	return ray &^ rays[direction][blocker]
+}
+
+// target returns the square shifted by the files and ranks, empty if it's out of the board.
+func target(square Square, files, ranks int) Bitboard {
+	file, rank := square.file()+files, square.rank()+ranks
+	if file < 0 || file > 7 || rank < 0 || rank > 7 {
+		return 0
+	}
+
+	return Square(rank*8 + file).Bitboard()
+}
@@ -0,0 +1,255 @@
+// Package bitboard contains the bitboard representation of the standard 8x8 chessboard
+// and precomputed attack tables for fast detection of attacked squares.
+package bitboard
+
+import (
+	"iter"
+	"math/bits"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/piece"
+)
+
+// Edge is the edge position of the boards that can be represented by bitboards.
+var Edge = chess.NewPosition(chess.FileH, chess.Rank8)
+
+// Bitboard is a set of squares of the 8x8 board, one bit per square.
+// The least significant bit is a1, the most significant one is h8.
+type Bitboard uint64
+
+// Square is an index of a square of the 8x8 board from 0 (a1) to 63 (h8).
+type Square int8
+
+// SquareOf returns the square of the position.
+// The second value is false if the position is out of the 8x8 board.
+func SquareOf(position chess.Position) (Square, bool) {
+	if position.File < chess.FileA || position.File > chess.FileH ||
+		position.Rank < chess.Rank1 || position.Rank > chess.Rank8 {
+		return 0, false
+	}
+
+	return Square((position.Rank-1)*8) + Square(position.File-1), true
+}
+
+// Has reports whether the square belongs to the set.
+func (b Bitboard) Has(square Square) bool {
+	return b&square.Bitboard() != 0
+}
+
+// Squares iterates over the squares of the set in ascending order.
+func (b Bitboard) Squares() iter.Seq[Square] {
+	return func(yield func(Square) bool) {
+		for b != 0 {
+			square := Square(bits.TrailingZeros64(uint64(b)))
+			if !yield(square) {
+				return
+			}
+
+			b &= b - 1
+		}
+	}
+}
+
+// Position returns the position of the square.
+func (s Square) Position() chess.Position {
+	return chess.NewPosition(chess.File(s.file()+1), chess.Rank(s.rank()+1))
+}
+
+// Bitboard returns the set consisting of the square only.
+func (s Square) Bitboard() Bitboard {
+	return 1 << s
+}
+
+func (s Square) file() int {
+	return int(s) % 8
+}
+
+func (s Square) rank() int {
+	return int(s) / 8
+}
+
+// Position is the placement of the pieces of the standard types on the 8x8 board.
+type Position struct {
+	pieces   [2][pieceTypes]Bitboard
+	occupied [2]Bitboard
+}
+
+// FromSquares returns the bitboard representation of the squares.
+// The second value is false if the board isn't 8x8 or contains a piece of a non-standard type.
+func FromSquares(squares *chess.Squares) (Position, bool) {
+	var p Position
+	if squares.EdgePosition() != Edge {
+		return p, false
+	}
+
+	// The squares are iterated from a1 to h8 rank by rank, so the index of the iteration is the square.
+	square := Square(0)
+	for _, pc := range squares.Iter() {
+		if pc != nil {
+			pieceType, ok := pieceTypeOf(pc.Notation())
+			if !ok {
+				return p, false
+			}
+
+			p.place(colorIndex(pc.Color()), pieceType, square)
+		}
+
+		square++
+	}
+
+	return p, true
+}
+
+// King returns the square of the king of the color.
+// The second value is false if there is no king of the color.
+func (p *Position) King(color chess.Color) (Square, bool) {
+	kings := p.pieces[colorIndex(color)][king]
+	if kings == 0 {
+		return 0, false
+	}
+
+	return Square(bits.TrailingZeros64(uint64(kings))), true
+}
+
+// Occupied returns the squares occupied by the pieces of the color.
+func (p *Position) Occupied(color chess.Color) Bitboard {
+	return p.occupied[colorIndex(color)]
+}
+
+// Move moves the piece from the square to another one, the piece on the destination square is captured.
+// Nothing happens if the initial square is empty.
+func (p *Position) Move(from, to Square) {
+	color, pieceType, ok := p.pieceAt(from)
+	if !ok {
+		return
+	}
+
+	p.Remove(to)
+	p.remove(color, pieceType, from)
+	p.place(color, pieceType, to)
+}
+
+// Remove removes the piece from the square.
+func (p *Position) Remove(square Square) {
+	mask := ^square.Bitboard()
+	for color := range p.pieces {
+		p.occupied[color] &= mask
+		for pieceType := range p.pieces[color] {
+			p.pieces[color][pieceType] &= mask
+		}
+	}
+}
+
+// IsAttacked reports whether the square is attacked by any piece of the color.
+func (p *Position) IsAttacked(square Square, by chess.Color) bool {
+	attacker := colorIndex(by)
+	pieces := &p.pieces[attacker]
+	occupied := p.occupied[0] | p.occupied[1]
+
+	switch {
+	case pawnAttacks[1-attacker][square]&pieces[pawn] != 0,
+		knightAttacks[square]&pieces[knight] != 0,
+		kingAttacks[square]&pieces[king] != 0,
+		BishopAttacks(square, occupied)&(pieces[bishop]|pieces[queen]) != 0,
+		RookAttacks(square, occupied)&(pieces[rook]|pieces[queen]) != 0:
+		return true
+	}
+
+	return false
+}
+
+// PseudoMoves returns the squares the piece on the square can move to without regard to checks.
+// The pawn advances two squares only if it isn't moved yet.
+// En passant captures and castling aren't included.
+func (p *Position) PseudoMoves(from Square, isPawnMoved bool) Bitboard {
+	color, pieceType, ok := p.pieceAt(from)
+	if !ok {
+		return 0
+	}
+
+	own, occupied := p.occupied[color], p.occupied[0]|p.occupied[1]
+
+	switch pieceType {
+	case pawn:
+		return p.pawnMoves(from, color, isPawnMoved)
+	case knight:
+		return knightAttacks[from] &^ own
+	case bishop:
+		return BishopAttacks(from, occupied) &^ own
+	case rook:
+		return RookAttacks(from, occupied) &^ own
+	case queen:
+		return (BishopAttacks(from, occupied) | RookAttacks(from, occupied)) &^ own
+	default:
+		return kingAttacks[from] &^ own
+	}
+}
+
+func (p *Position) pawnMoves(from Square, color int, isMoved bool) Bitboard {
+	rankDir := 1
+	if color == 1 {
+		rankDir = -1
+	}
+
+	empty := ^(p.occupied[0] | p.occupied[1])
+
+	moves := target(from, 0, rankDir) & empty
+	if moves != 0 && !isMoved {
+		moves |= target(from, 0, 2*rankDir) & empty
+	}
+
+	return moves | pawnAttacks[color][from]&p.occupied[1-color]
+}
+
+func (p *Position) pieceAt(square Square) (int, int, bool) {
+	for color := range p.pieces {
+		if !p.occupied[color].Has(square) {
+			continue
+		}
+
+		for pieceType := range p.pieces[color] {
+			if p.pieces[color][pieceType].Has(square) {
+				return color, pieceType, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+func (p *Position) place(color int, pieceType int, square Square) {
+	p.pieces[color][pieceType] |= square.Bitboard()
+	p.occupied[color] |= square.Bitboard()
+}
+
+func (p *Position) remove(color int, pieceType int, square Square) {
+	p.pieces[color][pieceType] &^= square.Bitboard()
+	p.occupied[color] &^= square.Bitboard()
+}
+
+func pieceTypeOf(notation string) (int, bool) {
+	switch notation {
+	case piece.NotationPawn:
+		return pawn, true
+	case piece.NotationKnight:
+		return knight, true
+	case piece.NotationBishop:
+		return bishop, true
+	case piece.NotationRook:
+		return rook, true
+	case piece.NotationQueen:
+		return queen, true
+	case piece.NotationKing:
+		return king, true
+	default:
+		return 0, false
+	}
+}
+
+func colorIndex(color chess.Color) int {
+	if color.IsBlack() {
+		return 1
+	}
+
+	return 0
+}
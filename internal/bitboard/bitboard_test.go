@@ -0,0 +1,174 @@
+package bitboard_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/bitboard"
+	"github.com/elaxer/standardchess/internal/standardtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSquareOf(t *testing.T) {
+	tests := []struct {
+		position chess.Position
+		want     bitboard.Square
+		wantOk   bool
+	}{
+		{chess.PositionFromString("a1"), 0, true},
+		{chess.PositionFromString("h1"), 7, true},
+		{chess.PositionFromString("a2"), 8, true},
+		{chess.PositionFromString("e4"), 28, true},
+		{chess.PositionFromString("h8"), 63, true},
+		{chess.PositionFromString("i1"), 0, false},
+		{chess.PositionFromString("a9"), 0, false},
+		{chess.NewPositionEmpty(), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.position.String(), func(t *testing.T) {
+			square, ok := bitboard.SquareOf(tt.position)
+
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, square)
+			if ok {
+				assert.Equal(t, tt.position, square.Position())
+			}
+		})
+	}
+}
+
+func TestRookAttacks(t *testing.T) {
+	occupied := squares("d6", "f4", "d2", "b4")
+
+	assert.ElementsMatch(
+		t,
+		[]string{"d5", "d6", "e4", "f4", "d3", "d2", "c4", "b4"},
+		names(bitboard.RookAttacks(square("d4"), occupied)),
+	)
+	assert.Len(t, names(bitboard.RookAttacks(square("a1"), 0)), 14)
+}
+
+func TestBishopAttacks(t *testing.T) {
+	occupied := squares("f6", "b2")
+
+	assert.ElementsMatch(
+		t,
+		[]string{"e5", "f6", "c5", "b6", "a7", "e3", "f2", "g1", "c3", "b2"},
+		names(bitboard.BishopAttacks(square("d4"), occupied)),
+	)
+	assert.Len(t, names(bitboard.BishopAttacks(square("h8"), 0)), 7)
+}
+
+func TestFromSquares(t *testing.T) {
+	board := standardtest.DecodeFEN("4k3/8/8/8/8/8/8/4K2R w K - 0 1")
+
+	position, ok := bitboard.FromSquares(board.Squares())
+	require.True(t, ok)
+
+	king, ok := position.King(chess.ColorBlack)
+	assert.True(t, ok)
+	assert.Equal(t, square("e8"), king)
+	assert.Equal(t, squares("e1", "h1"), position.Occupied(chess.ColorWhite))
+}
+
+func TestFromSquares_NotSupported(t *testing.T) {
+	_, ok := bitboard.FromSquares(chess.NewSquares(chess.NewPosition(chess.FileJ, chess.Rank10)))
+	assert.False(t, ok)
+}
+
+func TestPosition_IsAttacked(t *testing.T) {
+	board := standardtest.DecodeFEN("4k3/8/8/3n4/8/8/4p3/R3K3 w - - 0 1")
+	position, ok := bitboard.FromSquares(board.Squares())
+	require.True(t, ok)
+
+	tests := []struct {
+		square string
+		by     chess.Color
+		want   bool
+	}{
+		{"f1", chess.ColorBlack, true},
+		{"d1", chess.ColorBlack, true},
+		{"e1", chess.ColorBlack, false},
+		{"c3", chess.ColorBlack, true},
+		{"e3", chess.ColorBlack, true},
+		{"d4", chess.ColorBlack, false},
+		{"a8", chess.ColorWhite, true},
+		{"f8", chess.ColorWhite, false},
+		{"e2", chess.ColorWhite, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.square, func(t *testing.T) {
+			assert.Equal(t, tt.want, position.IsAttacked(square(tt.square), tt.by))
+		})
+	}
+}
+
+func TestPosition_Move(t *testing.T) {
+	board := standardtest.DecodeFEN("4k3/8/8/8/8/8/4p3/R3K3 w - - 0 1")
+	position, ok := bitboard.FromSquares(board.Squares())
+	require.True(t, ok)
+
+	position.Move(square("e1"), square("e2"))
+
+	assert.Equal(t, squares("a1", "e2"), position.Occupied(chess.ColorWhite))
+	assert.Zero(t, position.Occupied(chess.ColorBlack)&^squares("e8"))
+
+	king, _ := position.King(chess.ColorWhite)
+	assert.Equal(t, square("e2"), king)
+}
+
+func TestPosition_PseudoMoves(t *testing.T) {
+	board := standardtest.DecodeFEN("4k3/8/8/8/8/3p4/4P2P/R3K2N w - - 0 1")
+	position, ok := bitboard.FromSquares(board.Squares())
+	require.True(t, ok)
+
+	tests := []struct {
+		name        string
+		from        string
+		isPawnMoved bool
+		want        []string
+	}{
+		{"pawn", "e2", false, []string{"e3", "e4", "d3"}},
+		{"moved_pawn", "e2", true, []string{"e3", "d3"}},
+		{"pawn_blocked_second_step", "h2", false, []string{"h3", "h4"}},
+		{"rook", "a1", false, []string{"b1", "c1", "d1", "a2", "a3", "a4", "a5", "a6", "a7", "a8"}},
+		{"king", "e1", false, []string{"d1", "d2", "f1", "f2"}},
+		{"knight", "h1", false, []string{"g3", "f2"}},
+		{"empty", "e4", false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.want, names(position.PseudoMoves(square(tt.from), tt.isPawnMoved)))
+		})
+	}
+}
+
+func square(str string) bitboard.Square {
+	square, ok := bitboard.SquareOf(chess.PositionFromString(str))
+	if !ok {
+		panic("invalid square " + str)
+	}
+
+	return square
+}
+
+func squares(strs ...string) bitboard.Bitboard {
+	var b bitboard.Bitboard
+	for _, str := range strs {
+		b |= square(str).Bitboard()
+	}
+
+	return b
+}
+
+func names(b bitboard.Bitboard) []string {
+	result := make([]string, 0, 8)
+	for square := range b.Squares() {
+		result = append(result, square.Position().String())
+	}
+	slices.Sort(result)
+
+	return result
+}
@@ -17,7 +17,7 @@ func MakeMove(castlingType CastlingType, board chess.Board) (chess.Move, error)
 	}
 
 	king, kingPosition := board.Squares().FindPiece(piece.NotationKing, board.Turn())
-	rook, rookPosition, _, err := getRook(
+	rook, rookPosition, err := getRook(
 		fileDirection(castlingType),
 		board.Turn(),
 		board.Squares(),
@@ -59,7 +59,7 @@ func UndoMove(move *MoveResult, board chess.Board) error {
 		return err
 	}
 
-	kingPosition, rookPosition := pickPositions(move.CastlingType, rank(move.Side()))
+	kingPosition, rookPosition := move.KingNewPosition(), move.RookNewPosition()
 
 	king, err := board.Squares().FindByPosition(kingPosition)
 	if err != nil {
@@ -94,6 +94,14 @@ func UndoMove(move *MoveResult, board chess.Board) error {
 	return board.Squares().PlacePiece(rook, move.InitRookPosition)
 }
 
+// CastledPositions returns the positions of the king and the rook after castling on the rank.
+func CastledPositions(
+	castlingType CastlingType,
+	rank chess.Rank,
+) (kingPosition, rookPosition chess.Position) {
+	return pickPositions(castlingType, rank)
+}
+
 func pickPositions(
 	castlingType CastlingType,
 	rank chess.Rank,
@@ -23,6 +23,20 @@ func (r *MoveResult) CapturedPiece() chess.Piece {
 	return nil
 }
 
+// KingNewPosition returns the position of the king after the castling.
+func (r *MoveResult) KingNewPosition() chess.Position {
+	kingPosition, _ := pickPositions(r.CastlingType, r.InitKingPosition.Rank)
+
+	return kingPosition
+}
+
+// RookNewPosition returns the position of the rook after the castling.
+func (r *MoveResult) RookNewPosition() chess.Position {
+	_, rookPosition := pickPositions(r.CastlingType, r.InitKingPosition.Rank)
+
+	return rookPosition
+}
+
 func (r *MoveResult) Input() string {
 	return r.CastlingType.String()
 }
@@ -3,6 +3,7 @@ package castling
 import (
 	"errors"
 	"fmt"
+	"iter"
 
 	"github.com/elaxer/chess"
 	"github.com/elaxer/standardchess/internal/piece"
@@ -30,19 +31,19 @@ func ValidateMoveWithObstacle(
 	return validateMove(castlingType, side, board, false)
 }
 
-func validateMove(
-	castlingType CastlingType,
-	side chess.Color,
-	board chess.Board,
-	validateObstacle bool,
-) error {
+// ValidateRight checks whether the side still has the right to castle:
+// the king and the castling rook have not been moved yet.
+// Unlike ValidateMove, obstacles, checks and attacked squares are not taken into account.
+func ValidateRight(castlingType CastlingType, side chess.Color, board chess.Board) error {
 	king, kingPosition := board.Squares().FindPiece(piece.NotationKing, side)
-	if err := validateKing(king, side, board); err != nil {
-		return err
+	if king == nil {
+		return fmt.Errorf("%w: the king wasn't found", ErrValidation)
+	}
+	if king.IsMoved() {
+		return fmt.Errorf("%w: the king already has been moved", ErrValidation)
 	}
-	fileDir := fileDirection(castlingType)
 
-	rook, _, hasObstacle, err := getRook(fileDir, side, board.Squares(), kingPosition)
+	rook, _, err := getRook(fileDirection(castlingType), side, board.Squares(), kingPosition)
 	if err != nil {
 		return err
 	}
@@ -50,61 +51,200 @@ func validateMove(
 		return fmt.Errorf("%w: the rook already has been moved", ErrValidation)
 	}
 
-	if validateObstacle && hasObstacle {
-		return fmt.Errorf("%w: an obstacle", ErrValidation)
+	return nil
+}
+
+// RookPosition returns the position of the castling rook of the side in the given direction.
+// An error is returned if the side doesn't have the right to castle in this direction.
+func RookPosition(castlingType CastlingType, side chess.Color, board chess.Board) (chess.Position, error) {
+	if err := ValidateRight(castlingType, side, board); err != nil {
+		return chess.NewPositionEmpty(), err
 	}
 
-	kingNewPosition, rookNewPosition := pickPositions(castlingType, kingPosition.Rank)
+	_, kingPosition := board.Squares().FindPiece(piece.NotationKing, side)
+	_, rookPosition, err := getRook(fileDirection(castlingType), side, board.Squares(), kingPosition)
 
-	if side == board.Turn() &&
-		(board.IsSquareAttacked(kingNewPosition) || board.IsSquareAttacked(rookNewPosition)) {
-		return fmt.Errorf("%w: castling squares are under threat", ErrValidation)
+	return rookPosition, err
+}
 
+// RevokeRight deprives the side of the right to castle in the given direction
+// by marking all the rooks of the side in this direction as moved.
+// Nothing happens if there is no king.
+func RevokeRight(castlingType CastlingType, side chess.Color, board chess.Board) {
+	_, kingPosition := board.Squares().FindPiece(piece.NotationKing, side)
+	if kingPosition.IsEmpty() {
+		return
 	}
 
-	return nil
+	direction := chess.NewPosition(fileDirection(castlingType), 0)
+	for _, p := range board.Squares().IterByDirection(kingPosition, direction) {
+		if p != nil && p.Color() == side && p.Notation() == piece.NotationRook {
+			p.SetIsMoved(true)
+		}
+	}
 }
 
-func validateKing(king chess.Piece,
-	side chess.Color,
-	board chess.Board) error {
+// GrantRight makes the rook on the file the castling rook of the side in the given direction,
+// as the file letters of Shredder-FEN and X-FEN do.
+// The rooks of the side beyond it are marked as moved.
+func GrantRight(castlingType CastlingType, side chess.Color, board chess.Board, rookFile chess.File) error {
+	king, kingPosition := board.Squares().FindPiece(piece.NotationKing, side)
 	if king == nil {
 		return fmt.Errorf("%w: the king wasn't found", ErrValidation)
 	}
-	if king.IsMoved() {
-		return fmt.Errorf("%w: the king already has been moved", ErrValidation)
+
+	direction := chess.NewPosition(fileDirection(castlingType), 0)
+
+	var rook chess.Piece
+	for position, p := range board.Squares().IterByDirection(kingPosition, direction) {
+		if p == nil || p.Color() != side || p.Notation() != piece.NotationRook {
+			continue
+		}
+
+		if position.File == rookFile {
+			rook = p
+		} else if rook != nil {
+			p.SetIsMoved(true)
+		}
+	}
+
+	if rook == nil {
+		return fmt.Errorf("%w: rook wasn't found", ErrValidation)
+	}
+
+	rook.SetIsMoved(false)
+	king.SetIsMoved(false)
+
+	return nil
+}
+
+// validateMove checks the castling by the Chess960 rules, which are the same as the standard ones
+// for the standard initial position: all the squares between the king and its destination
+// and between the rook and its destination must be empty except for the castling king and rook,
+// the king must not pass attacked squares or end up in check.
+func validateMove(
+	castlingType CastlingType,
+	side chess.Color,
+	board chess.Board,
+	validateObstacle bool,
+) error {
+	if err := ValidateRight(castlingType, side, board); err != nil {
+		return err
 	}
 	if side == board.Turn() && !board.State().Type().IsClear() {
 		return fmt.Errorf("%w: the king is under threat", ErrValidation)
 	}
 
+	king, kingPosition := board.Squares().FindPiece(piece.NotationKing, side)
+	rook, rookPosition, err := getRook(fileDirection(castlingType), side, board.Squares(), kingPosition)
+	if err != nil {
+		return err
+	}
+
+	kingNewPosition, rookNewPosition := pickPositions(castlingType, kingPosition.Rank)
+
+	hasObstacle := isObstructed(board.Squares(), kingPosition, kingNewPosition, king, rook) ||
+		isObstructed(board.Squares(), rookPosition, rookNewPosition, king, rook)
+	if validateObstacle && hasObstacle {
+		return fmt.Errorf("%w: an obstacle", ErrValidation)
+	}
+
+	if side != board.Turn() {
+		return nil
+	}
+
+	for file := range files(kingPosition.File, kingNewPosition.File) {
+		if file == kingPosition.File {
+			continue
+		}
+		if board.IsSquareAttacked(chess.NewPosition(file, kingPosition.Rank)) {
+			return fmt.Errorf("%w: castling squares are under threat", ErrValidation)
+		}
+	}
+
+	isCheck := !hasObstacle &&
+		isCheckAfter(board, king, rook, kingPosition, rookPosition, kingNewPosition, rookNewPosition)
+	if isCheck {
+		return fmt.Errorf("%w: the king will be under threat", ErrValidation)
+	}
+
 	return nil
 }
 
+// getRook returns the castling rook: the outermost unmoved rook of the side in the direction from the king
+// or the outermost rook if all of them are moved.
 func getRook(
 	fileDir chess.File,
 	side chess.Color,
 	squares *chess.Squares,
 	kingPosition chess.Position,
-) (chess.Piece, chess.Position, bool, error) {
-	hasObstacle := false
+) (chess.Piece, chess.Position, error) {
+	var (
+		rook         chess.Piece
+		rookPosition chess.Position
+	)
 	for position, p := range squares.IterByDirection(kingPosition, chess.NewPosition(fileDir, 0)) {
-		if p == nil {
+		if p == nil || p.Color() != side || p.Notation() != piece.NotationRook {
 			continue
 		}
-		if p.Color() != side || p.Notation() != piece.NotationRook {
-			hasObstacle = true
-
-			continue
+		if rook == nil || rook.IsMoved() || !p.IsMoved() {
+			rook, rookPosition = p, position
 		}
+	}
 
-		return p, position, hasObstacle, nil
+	if rook == nil {
+		return nil, chess.NewPositionEmpty(), fmt.Errorf("%w: rook wasn't found", ErrValidation)
 	}
 
-	return nil, chess.NewPositionEmpty(), hasObstacle, fmt.Errorf(
-		"%w: rook wasn't found",
-		ErrValidation,
-	)
+	return rook, rookPosition, nil
+}
+
+// isObstructed reports whether there is a piece other than the king and the rook
+// on the squares of the rank from one position to another inclusive.
+func isObstructed(squares *chess.Squares, from, to chess.Position, king, rook chess.Piece) bool {
+	for file := range files(from.File, to.File) {
+		p, err := squares.FindByPosition(chess.NewPosition(file, from.Rank))
+		if err != nil || (p != nil && p != king && p != rook) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isCheckAfter reports whether the king is attacked after the castling.
+// It catches the attacks along the rank which are blocked by the castling rook before the castling.
+func isCheckAfter(
+	board chess.Board,
+	king, rook chess.Piece,
+	kingPosition, rookPosition, kingNewPosition, rookNewPosition chess.Position,
+) bool {
+	squares := board.Squares()
+
+	_ = squares.PlacePiece(nil, kingPosition)
+	_ = squares.PlacePiece(nil, rookPosition)
+	_ = squares.PlacePiece(king, kingNewPosition)
+	_ = squares.PlacePiece(rook, rookNewPosition)
+
+	isAttacked := board.IsSquareAttacked(kingNewPosition)
+
+	_ = squares.PlacePiece(nil, kingNewPosition)
+	_ = squares.PlacePiece(nil, rookNewPosition)
+	_ = squares.PlacePiece(king, kingPosition)
+	_ = squares.PlacePiece(rook, rookPosition)
+
+	return isAttacked
+}
+
+// files iterates over the files between two files inclusive.
+func files(from, to chess.File) iter.Seq[chess.File] {
+	return func(yield func(chess.File) bool) {
+		for file := min(from, to); file <= max(from, to); file++ {
+			if !yield(file) {
+				return
+			}
+		}
+	}
 }
 
 func fileDirection(castlingType CastlingType) chess.File {
@@ -70,6 +70,18 @@ func TestValidateMove(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"rook_between_king_and_castling_rook",
+			args{
+				castling.TypeShort,
+				standardtest.NewBoardEmpty8x8(chess.ColorWhite, map[chess.Position]chess.Piece{
+					chess.PositionFromString("e1"): standardtest.NewPiece("K"),
+					chess.PositionFromString("g1"): standardtest.NewPiece("R"),
+					chess.PositionFromString("h1"): standardtest.NewPiece("R"),
+				}),
+			},
+			true,
+		},
 		{
 			"opposite_side_rook",
 			args{
@@ -134,6 +146,43 @@ func TestValidateMove(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"chess960_king_stays_on_its_square",
+			args{
+				castling.TypeShort,
+				standardtest.NewBoardEmpty8x8(chess.ColorWhite, map[chess.Position]chess.Piece{
+					chess.PositionFromString("g1"): standardtest.NewPiece("K"),
+					chess.PositionFromString("h1"): standardtest.NewPiece("R"),
+					chess.PositionFromString("e8"): standardtest.NewPiece("k"),
+				}),
+			},
+			false,
+		},
+		{
+			"chess960_rook_shields_king_destination",
+			args{
+				castling.TypeLong,
+				standardtest.NewBoardEmpty8x8(chess.ColorWhite, map[chess.Position]chess.Piece{
+					chess.PositionFromString("d1"): standardtest.NewPiece("K"),
+					chess.PositionFromString("b1"): standardtest.NewPiece("R"),
+					chess.PositionFromString("a1"): standardtest.NewPiece("r"),
+					chess.PositionFromString("e8"): standardtest.NewPiece("k"),
+				}),
+			},
+			true,
+		},
+		{
+			"chess960_rook_destination_is_occupied",
+			args{
+				castling.TypeShort,
+				standardtest.NewBoardEmpty8x8(chess.ColorWhite, map[chess.Position]chess.Piece{
+					chess.PositionFromString("g1"): standardtest.NewPiece("K"),
+					chess.PositionFromString("h1"): standardtest.NewPiece("R"),
+					chess.PositionFromString("f1"): standardtest.NewPiece("B"),
+				}),
+			},
+			true,
+		},
 		{
 			"another_piece_instead_rook",
 			args{castling.TypeShort, standardtest.DecodeFEN("12/12/12/12/12/3K3P2N1")},
@@ -7,15 +7,19 @@ import (
 	"github.com/elaxer/chess"
 	"github.com/elaxer/standardchess/internal/move/normal"
 	"github.com/elaxer/standardchess/internal/piece"
+	"github.com/elaxer/standardchess/internal/setup"
 )
 
 func CanEnPassant(board chess.Board) bool {
 	return !EnPassantTargetSquare(board).IsEmpty()
 }
 
+// EnPassantTargetSquare returns the square passed over by the pawn which made a two-square move
+// on the last move. If there are no moves on the board yet,
+// the en passant target square of the board setup is returned.
 func EnPassantTargetSquare(board chess.Board) chess.Position {
 	if len(board.MoveHistory()) == 0 {
-		return chess.NewPositionEmpty()
+		return setup.FromBoard(board).EnPassantSquare
 	}
 
 	lastMove := board.MoveHistory()[len(board.MoveHistory())-1]
@@ -34,6 +38,25 @@ func EnPassantTargetSquare(board chess.Board) chess.Position {
 	)
 }
 
+// LegalTargetSquare returns the en passant target square
+// only if the side to move can legally capture en passant on it.
+// Otherwise it returns an empty position.
+func LegalTargetSquare(board chess.Board) chess.Position {
+	target := EnPassantTargetSquare(board)
+	if target.IsEmpty() {
+		return target
+	}
+
+	for _, file := range [2]chess.File{target.File - 1, target.File + 1} {
+		from := chess.NewPosition(file, enPassantRank(board.Turn()))
+		if ValidateMove(from, target, board) == nil {
+			return target
+		}
+	}
+
+	return chess.NewPositionEmpty()
+}
+
 func enPassantRank(side chess.Color) chess.Rank {
 	if side.IsBlack() {
 		return chess.Rank4
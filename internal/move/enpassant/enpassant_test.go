@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
 	"github.com/elaxer/standardchess/internal/move/enpassant"
 	"github.com/elaxer/standardchess/internal/standardtest"
 	"github.com/stretchr/testify/assert"
@@ -24,3 +25,20 @@ func TestEnPassantTargetSquare(t *testing.T) {
 	pawnMoves := board.LegalMoves(pawn)
 	assert.Contains(t, pawnMoves, position)
 }
+
+func TestLegalTargetSquare(t *testing.T) {
+	board := standardtest.DecodeFEN("rnQ4r/pp2p1kp/3p2pn/1BpP2N1/5P2/2BK4/P1P3qP/8 b - - 5 18")
+	_, err := board.MakeMove("e5")
+	require.NoError(t, err)
+
+	assert.Equal(t, chess.PositionFromString("e6"), enpassant.LegalTargetSquare(board))
+}
+
+func TestLegalTargetSquare_NoCapturingPawn(t *testing.T) {
+	board := standardchess.NewBoard()
+	_, err := board.MakeMove("e4")
+	require.NoError(t, err)
+
+	assert.Equal(t, chess.PositionFromString("e3"), enpassant.EnPassantTargetSquare(board))
+	assert.True(t, enpassant.LegalTargetSquare(board).IsEmpty())
+}
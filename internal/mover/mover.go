@@ -19,7 +19,11 @@ var (
 	ErrUndoMove = fmt.Errorf("%w: cannot undo move", Err)
 )
 
+// MakeMove makes the move given in the Standard Algebraic Notation or in the UCI long algebraic notation.
 func MakeMove(moveStr string, board chess.Board) (chess.Move, error) {
+	if notation, ok := fromUCI(moveStr, board); ok {
+		moveStr = notation
+	}
 
 	if move, err := normal.MoveFromString(moveStr); err == nil {
 		isPawn := move.PieceNotation == piece.NotationPawn
@@ -0,0 +1,69 @@
+package mover
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/rgx"
+	"github.com/elaxer/standardchess/internal/move/castling"
+	"github.com/elaxer/standardchess/internal/piece"
+	"github.com/elaxer/standardchess/internal/setup"
+)
+
+var regexpUCI = regexp.MustCompile(
+	"^(?P<from>[a-p](1[0-6]|[1-9]))(?P<to>[a-p](1[0-6]|[1-9]))(?P<promoted_piece>[qrbn])?$",
+)
+
+// fromUCI converts a move in the UCI long algebraic notation, e.g. "g1f3", "e7e8q" or "e1g1",
+// to the notation the move parsers understand: "Ng1f3", "e7e8=Q" or "O-O".
+// Castling is also accepted in the Chess960 form "king to the castling rook", e.g. "e1h1".
+// On Chess960 boards it's the only accepted form of castling.
+// The second value is false if the move isn't in UCI notation or there is no piece on the initial square.
+func fromUCI(moveStr string, board chess.Board) (string, bool) {
+	data, err := rgx.Group(regexpUCI, moveStr)
+	if err != nil {
+		return "", false
+	}
+
+	from, to := chess.PositionFromString(data["from"]), chess.PositionFromString(data["to"])
+
+	p, err := board.Squares().FindByPosition(from)
+	if err != nil || p == nil {
+		return "", false
+	}
+
+	if p.Notation() == piece.NotationKing && from.Rank == to.Rank {
+		if castlingType, ok := castlingFromUCI(p, from, to, board); ok {
+			return castlingType.String(), true
+		}
+	}
+
+	if data["promoted_piece"] != "" {
+		return from.String() + to.String() + "=" + strings.ToUpper(data["promoted_piece"]), true
+	}
+
+	return p.Notation() + from.String() + to.String(), true
+}
+
+func castlingFromUCI(king chess.Piece, from, to chess.Position, board chess.Board) (castling.CastlingType, bool) {
+	castlingType := castling.TypeShort
+	if to.File < from.File {
+		castlingType = castling.TypeLong
+	}
+
+	if rook, err := board.Squares().FindByPosition(to); err == nil && rook != nil &&
+		rook.Color() == king.Color() && rook.Notation() == piece.NotationRook {
+		rookPosition, err := castling.RookPosition(castlingType, king.Color(), board)
+
+		return castlingType, err == nil && rookPosition == to
+	}
+
+	if setup.FromBoard(board).Chess960 {
+		return castlingType, false
+	}
+
+	distance := to.File - from.File
+
+	return castlingType, distance == 2 || distance == -2
+}
@@ -23,6 +23,17 @@ func PawnRankDirection(color chess.Color) chess.Rank {
 	return 1
 }
 
+// PawnAttacks returns the positions diagonally in front of the pawn of the color, which the pawn attacks.
+// The positions may be out of the board.
+func PawnAttacks(from chess.Position, color chess.Color) [2]chess.Position {
+	rankDir := PawnRankDirection(color)
+
+	return [2]chess.Position{
+		chess.NewPosition(from.File+1, from.Rank+rankDir),
+		chess.NewPosition(from.File-1, from.Rank+rankDir),
+	}
+}
+
 func NewPawn(color chess.Color) *Pawn {
 	return &Pawn{&abstract{color, false}}
 }
@@ -31,7 +42,7 @@ func (p *Pawn) PseudoMoves(from chess.Position, squares *chess.Squares) []chess.
 	direction := PawnRankDirection(p.color)
 	moves := make([]chess.Position, 0, 4)
 	p.appendMovesForward(&moves, from, direction, squares)
-	p.appendMovesDiagonal(&moves, from, direction, squares)
+	p.appendMovesDiagonal(&moves, from, squares)
 
 	return moves
 }
@@ -84,14 +95,9 @@ func (p *Pawn) appendMovesForward(
 func (p *Pawn) appendMovesDiagonal(
 	moves *[]chess.Position,
 	from chess.Position,
-	rankDir chess.Rank,
 	squares *chess.Squares,
 ) {
-	positions := [2]chess.Position{
-		chess.NewPosition(from.File+1, from.Rank+rankDir),
-		chess.NewPosition(from.File-1, from.Rank+rankDir),
-	}
-	for _, move := range positions {
+	for _, move := range PawnAttacks(from, p.color) {
 		piece, err := squares.FindByPosition(move)
 		if err == nil && piece != nil && piece.Color() != p.color {
 			*moves = append(*moves, move)
@@ -0,0 +1,147 @@
+// Package protocol contains the plumbing shared by the text protocols of the chess engines, UCI and CECP:
+// the connection to an engine reading the lines of its output, the engine subprocess and the parsing of the commands.
+package protocol
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/elaxer/standardchess"
+)
+
+// closeTimeout is the time the engine subprocess is given to quit before it's killed.
+const closeTimeout = 5 * time.Second
+
+var ErrEngineExited = errors.New("the engine has exited")
+
+// Conn is a connection to an engine: the commands are written to the engine line by line
+// and the lines of its output are read in the background.
+// A connection must not be used concurrently.
+type Conn struct {
+	w       io.Writer
+	lines   chan string
+	readErr error
+	closed  chan struct{}
+
+	cmd   *exec.Cmd
+	stdin io.Closer
+}
+
+// NewConn creates the connection reading the engine output from r and writing the commands to w.
+// Up to buffer lines of the output are read ahead, e.g. while the commands are sent.
+func NewConn(r io.Reader, w io.Writer, buffer int) *Conn {
+	c := &Conn{w: w, lines: make(chan string, buffer), closed: make(chan struct{})}
+	go c.read(r)
+
+	return c
+}
+
+// Start starts the engine subprocess with the arguments and connects to it, see NewConn.
+// The connection must be closed after use.
+func Start(buffer int, name string, args ...string) (*Conn, error) {
+	cmd := exec.Command(name, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	c := NewConn(stdout, stdin, buffer)
+	c.cmd = cmd
+	c.stdin = stdin
+
+	return c, nil
+}
+
+// Send writes the command to the engine. ErrEngineExited is returned if the command can't be written.
+func (c *Conn) Send(command string) error {
+	if _, err := io.WriteString(c.w, command+"\n"); err != nil {
+		return fmt.Errorf("%w: %w", ErrEngineExited, err)
+	}
+
+	return nil
+}
+
+// ReadLine returns the next line of the engine output.
+// ErrEngineExited is returned if the output is closed and the error of the context if it's done.
+func (c *Conn) ReadLine(ctx context.Context) (string, error) {
+	select {
+	case line, ok := <-c.lines:
+		if !ok {
+			if c.readErr != nil {
+				return "", fmt.Errorf("%w: %w", ErrEngineExited, c.readErr)
+			}
+
+			return "", ErrEngineExited
+		}
+
+		return line, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Close sends the quit command to the engine and stops reading its output.
+// The engine subprocess is killed if it doesn't quit in time.
+func (c *Conn) Close(quit string) error {
+	select {
+	case <-c.closed:
+		return nil
+	default:
+	}
+
+	sendErr := c.Send(quit)
+	close(c.closed)
+	if c.cmd == nil {
+		return sendErr
+	}
+
+	_ = c.stdin.Close()
+	timer := time.AfterFunc(closeTimeout, func() {
+		_ = c.cmd.Process.Kill()
+	})
+	defer timer.Stop()
+
+	return c.cmd.Wait()
+}
+
+// read sends the lines of the engine output to the lines channel until the output or the connection is closed.
+func (c *Conn) read(r io.Reader) {
+	defer close(c.lines)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		select {
+		case c.lines <- scanner.Text():
+		case <-c.closed:
+			return
+		}
+	}
+	c.readErr = scanner.Err()
+}
+
+// CutCommand returns the first word of the line and the rest of it.
+func CutCommand(line string) (command, args string) {
+	command, args, _ = strings.Cut(strings.TrimSpace(line), " ")
+
+	return command, strings.TrimSpace(args)
+}
+
+// SearchBoard switches the board to the bitboard backend which makes the search much faster.
+func SearchBoard(board standardchess.Board) standardchess.Board {
+	_ = standardchess.SetBackend(board, standardchess.BackendBitboard)
+
+	return board
+}
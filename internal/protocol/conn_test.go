@@ -0,0 +1,56 @@
+package protocol_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/elaxer/standardchess/internal/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn_ReadLine(t *testing.T) {
+	var out bytes.Buffer
+	conn := protocol.NewConn(strings.NewReader("id name Engine\nuciok\n"), &out, 0)
+
+	require.NoError(t, conn.Send("uci"))
+	assert.Equal(t, "uci\n", out.String())
+
+	for _, expected := range []string{"id name Engine", "uciok"} {
+		line, err := conn.ReadLine(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, expected, line)
+	}
+
+	_, err := conn.ReadLine(context.Background())
+	assert.ErrorIs(t, err, protocol.ErrEngineExited)
+}
+
+func TestConn_Close(t *testing.T) {
+	var out bytes.Buffer
+	conn := protocol.NewConn(strings.NewReader("readyok\n"), &out, 0)
+
+	require.NoError(t, conn.Close("quit"))
+	require.NoError(t, conn.Close("quit"))
+	assert.Equal(t, "quit\n", out.String())
+}
+
+func TestCutCommand(t *testing.T) {
+	tests := []struct {
+		line          string
+		command, args string
+	}{
+		{"uciok", "uciok", ""},
+		{"  id name  Engine 1.0 ", "id", "name  Engine 1.0"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			command, args := protocol.CutCommand(tt.line)
+			assert.Equal(t, tt.command, command)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
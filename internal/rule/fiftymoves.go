@@ -1,30 +1,15 @@
 package rule
 
 import (
-	"slices"
-
 	"github.com/elaxer/chess"
-	"github.com/elaxer/standardchess/internal/move/normal"
-	"github.com/elaxer/standardchess/internal/piece"
 	"github.com/elaxer/standardchess/internal/state"
+	"github.com/elaxer/standardchess/metric"
 )
 
+// FiftyMoves checks whether the last fifty moves of each side
+// were made without a pawn advance or a capture.
 func FiftyMoves(board chess.Board) chess.State {
-	moves := slices.Clone(board.MoveHistory())
-	slices.Reverse(moves)
-
-	count := 0
-	for _, move := range moves {
-		normalMove, ok := move.(*normal.MoveResult)
-		if !ok || normalMove.InputMove.PieceNotation == piece.NotationPawn ||
-			normalMove.IsCapture() {
-			count = 0
-		} else {
-			count++
-		}
-	}
-
-	if count >= 50 {
+	if clock, _ := metric.HalfmoveClock(board).Value().(int); clock >= 100 {
 		return state.FiftyMoves
 	}
 
@@ -0,0 +1,111 @@
+package rule
+
+import (
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/piece"
+	"github.com/elaxer/standardchess/internal/state"
+)
+
+// material counts the pieces of a side except the king.
+type material struct {
+	knights, lightBishops, darkBishops int
+	// others are the pawns, rooks, queens and pieces of the other types, pawns and queens are also counted separately.
+	others, pawns, queens int
+}
+
+// InsufficientMaterial checks whether neither side can checkmate with the remaining pieces.
+// These cases are covered: king against king, king and bishop against king,
+// king and knight against king, kings and bishops where all bishops stand on squares of the same color.
+// The color of a square is calculated from its file and rank, so boards of any size are supported.
+func InsufficientMaterial(board chess.Board) chess.State {
+	knights := 0
+	bishopSquareColors := make(map[bool]bool, 2)
+	for position, p := range board.Squares().Iter() {
+		if p == nil {
+			continue
+		}
+
+		switch p.Notation() {
+		case piece.NotationKing:
+		case piece.NotationKnight:
+			knights++
+		case piece.NotationBishop:
+			bishopSquareColors[isLightSquare(position)] = true
+		default:
+			return nil
+		}
+	}
+
+	if (knights == 0 && len(bishopSquareColors) <= 1) ||
+		(knights == 1 && len(bishopSquareColors) == 0) {
+		return state.InsufficientMaterial
+	}
+
+	return nil
+}
+
+func isLightSquare(position chess.Position) bool {
+	return (int(position.File)+int(position.Rank))%2 == 1
+}
+
+// CanCheckmate reports whether the side could checkmate the opponent by any series of legal moves,
+// the pieces of the opponent may help by blocking its king. A lone king can't checkmate,
+// a single knight needs any blocking pieces except queens, bishops standing on squares of the same color
+// need pawns, knights or bishops of the other square color.
+func CanCheckmate(board chess.Board, color chess.Color) bool {
+	var own, opponent material
+	for position, p := range board.Squares().Iter() {
+		if p == nil || p.Notation() == piece.NotationKing {
+			continue
+		}
+
+		if p.Color() == color {
+			own.add(p, position)
+		} else {
+			opponent.add(p, position)
+		}
+	}
+
+	switch {
+	case own.others > 0 || own.knights > 1 || (own.knights > 0 && own.bishops() > 0):
+		return true
+	case own.knights == 1:
+		return opponent.others-opponent.queens > 0 || opponent.knights > 0 || opponent.bishops() > 0
+	case own.bishops() == 0:
+		return false
+	case own.lightBishops > 0 && own.darkBishops > 0:
+		return true
+	}
+
+	oppositeBishops := opponent.darkBishops
+	if own.darkBishops > 0 {
+		oppositeBishops = opponent.lightBishops
+	}
+
+	return opponent.pawns > 0 || opponent.knights > 0 || oppositeBishops > 0
+}
+
+func (m *material) add(p chess.Piece, position chess.Position) {
+	switch p.Notation() {
+	case piece.NotationKnight:
+		m.knights++
+	case piece.NotationBishop:
+		if isLightSquare(position) {
+			m.lightBishops++
+		} else {
+			m.darkBishops++
+		}
+	case piece.NotationPawn:
+		m.pawns++
+		m.others++
+	case piece.NotationQueen:
+		m.queens++
+		m.others++
+	default:
+		m.others++
+	}
+}
+
+func (m *material) bishops() int {
+	return m.lightBishops + m.darkBishops
+}
@@ -0,0 +1,79 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/rule"
+	"github.com/elaxer/standardchess/internal/standardtest"
+	"github.com/elaxer/standardchess/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsufficientMaterial(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		want chess.State
+	}{
+		{"king_vs_king", "8/8/3k4/8/8/4K3/8/8 w", state.InsufficientMaterial},
+		{"king_bishop_vs_king", "8/8/3k4/8/8/4K3/5B2/8 w", state.InsufficientMaterial},
+		{"king_vs_king_knight", "8/8/3k4/2n5/8/4K3/8/8 b", state.InsufficientMaterial},
+		{"bishops_same_color", "8/8/3k4/2b5/8/4K3/5B2/8 w", state.InsufficientMaterial},
+		{"bishops_opposite_color", "8/8/3k4/3b4/8/4K3/5B2/8 w", nil},
+		{"two_knights", "8/8/3k4/8/8/4K3/3N1N2/8 w", nil},
+		{"knight_vs_bishop", "8/8/3k4/2b5/8/4K3/3N4/8 w", nil},
+		{"pawn", "8/8/3k4/8/8/4K3/3P4/8 w", nil},
+		{"rook", "8/8/3k4/8/8/4K3/3r4/8 w", nil},
+		{"10x10_bishops_same_color", "10/10/10/3k6/2b7/10/4K5/5B4/10/10 w", state.InsufficientMaterial},
+		{"10x10_bishops_opposite_color", "10/10/10/3k6/3b6/10/4K5/5B4/10/10 w", nil},
+		{"6x6_king_knight_vs_king", "6/2k3/6/6/1NK3/6 b", state.InsufficientMaterial},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rule.InsufficientMaterial(standardtest.DecodeFEN(tt.fen)))
+		})
+	}
+}
+
+func TestInsufficientMaterial_AfterCapture(t *testing.T) {
+	board := standardtest.DecodeFEN("8/8/3k4/8/8/3K4/3r1B2/8 w")
+	require.Equal(t, state.Check, board.State())
+
+	_, err := board.MakeMove("Kxd2")
+	require.NoError(t, err)
+	assert.Equal(t, state.InsufficientMaterial, board.State())
+
+	_, err = board.MakeMove("Ke6")
+	assert.Error(t, err)
+}
+
+func TestCanCheckmate(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		color chess.Color
+		want  bool
+	}{
+		{"lone_king", "8/8/3k4/8/8/4K3/3q4/8 w", chess.ColorWhite, false},
+		{"queen", "8/8/3k4/8/8/4K3/3q4/8 w", chess.ColorBlack, true},
+		{"pawn", "8/8/3k4/8/8/4K3/3P4/8 w", chess.ColorWhite, true},
+		{"knight_vs_king", "8/8/3k4/8/8/4K3/3N4/8 w", chess.ColorWhite, false},
+		{"knight_vs_queen", "8/8/3k4/3q4/8/4K3/3N4/8 w", chess.ColorWhite, false},
+		{"knight_vs_pawn", "8/8/3k4/3p4/8/4K3/3N4/8 w", chess.ColorWhite, true},
+		{"two_knights", "8/8/3k4/8/8/4K3/3N1N2/8 w", chess.ColorWhite, true},
+		{"knight_and_bishop", "8/8/3k4/8/8/4K3/3N1B2/8 w", chess.ColorWhite, true},
+		{"bishop_vs_king", "8/8/3k4/8/8/4K3/5B2/8 w", chess.ColorWhite, false},
+		{"bishop_vs_rook", "8/8/3k4/3r4/8/4K3/5B2/8 w", chess.ColorWhite, false},
+		{"bishop_vs_knight", "8/8/3k4/3n4/8/4K3/5B2/8 w", chess.ColorWhite, true},
+		{"bishops_same_color", "8/8/3k4/2b5/8/4K3/5B2/8 w", chess.ColorWhite, false},
+		{"bishops_opposite_color", "8/8/3k4/3b4/8/4K3/5B2/8 w", chess.ColorWhite, true},
+		{"bishops_of_both_colors", "8/8/3k4/8/8/4K3/4BB2/8 w", chess.ColorWhite, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rule.CanCheckmate(standardtest.DecodeFEN(tt.fen), tt.color))
+		})
+	}
+}
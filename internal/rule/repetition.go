@@ -0,0 +1,40 @@
+package rule
+
+import (
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/state"
+)
+
+// positionRecorder is implemented by boards that record every position that occurred on them.
+type positionRecorder interface {
+	// PositionHashes returns the hashes of the occurred positions in chronological order.
+	// The last hash belongs to the current position.
+	PositionHashes() []uint64
+}
+
+func ThreefoldRepetition(board chess.Board) chess.State {
+	recorder, ok := board.(positionRecorder)
+	if !ok {
+		return nil
+	}
+
+	hashes := recorder.PositionHashes()
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	current := hashes[len(hashes)-1]
+
+	count := 0
+	for _, hash := range hashes {
+		if hash == current {
+			count++
+		}
+	}
+
+	if count >= 3 {
+		return state.ThreefoldRepetition
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/internal/rule"
+	"github.com/elaxer/standardchess/internal/standardtest"
+	"github.com/elaxer/standardchess/internal/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreefoldRepetition(t *testing.T) {
+	tests := []struct {
+		name  string
+		board chess.Board
+		moves []string
+		want  chess.State
+	}{
+		{
+			"knights_dance",
+			standardchess.NewBoard(),
+			[]string{"Nf3", "Nf6", "Ng1", "Ng8", "Nf3", "Nf6", "Ng1", "Ng8"},
+			state.ThreefoldRepetition,
+		},
+		{
+			"twofold",
+			standardchess.NewBoard(),
+			[]string{"Nf3", "Nf6", "Ng1", "Ng8"},
+			nil,
+		},
+		{
+			"castling_rights_lost",
+			standardtest.DecodeFEN("r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1"),
+			[]string{"Ke2", "Ke7", "Ke1", "Ke8", "Ke2", "Ke7", "Ke1", "Ke8"},
+			nil,
+		},
+		{
+			"castling_rights_lost_repeated",
+			standardtest.DecodeFEN("r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1"),
+			[]string{"Ke2", "Ke7", "Ke1", "Ke8", "Ke2", "Ke7", "Ke1", "Ke8", "Ke2", "Ke7"},
+			state.ThreefoldRepetition,
+		},
+		{
+			"decoded_position_is_counted",
+			standardtest.DecodeFEN("4k3/p7/8/8/8/8/P7/4K1N1 w - - 0 1"),
+			[]string{"Nf3", "Kd8", "Ng1", "Ke8", "Nf3", "Kd8", "Ng1", "Ke8"},
+			state.ThreefoldRepetition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, move := range tt.moves {
+				_, err := tt.board.MakeMove(move)
+				require.NoError(t, err, move)
+			}
+
+			assert.Equal(t, tt.want, rule.ThreefoldRepetition(tt.board))
+		})
+	}
+}
+
+func TestThreefoldRepetition_EnPassantAvailability(t *testing.T) {
+	// Black can capture en passant only right after e4,
+	// so that position differs from the same placement reached after Ng1.
+	board := standardtest.DecodeFEN("4k3/8/8/8/3p4/8/4P3/4K1N1 w - - 0 1")
+	for _, move := range []string{"e4", "Kd7", "Nf3", "Ke8", "Ng1", "Kd7", "Nf3", "Ke8", "Ng1"} {
+		_, err := board.MakeMove(move)
+		require.NoError(t, err, move)
+	}
+	assert.Nil(t, rule.ThreefoldRepetition(board))
+
+	_, err := board.MakeMove("Kd7")
+	require.NoError(t, err)
+	assert.Equal(t, state.ThreefoldRepetition, rule.ThreefoldRepetition(board))
+}
+
+func TestThreefoldRepetition_Undo(t *testing.T) {
+	board := standardchess.NewBoard()
+	for _, move := range []string{"Nf3", "Nf6", "Ng1", "Ng8", "Nf3", "Nf6", "Ng1", "Ng8"} {
+		_, err := board.MakeMove(move)
+		require.NoError(t, err, move)
+	}
+	require.Equal(t, state.ThreefoldRepetition, board.State())
+
+	_, err := board.UndoLastMove()
+	require.NoError(t, err)
+	assert.Equal(t, chess.StateClear, board.State())
+
+	_, err = board.MakeMove("Ng8")
+	require.NoError(t, err)
+	assert.Equal(t, state.ThreefoldRepetition, board.State())
+}
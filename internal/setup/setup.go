@@ -0,0 +1,38 @@
+// Package setup contains the information about the initial position of a board
+// that cannot be restored from the placement of pieces and the move history.
+package setup
+
+import "github.com/elaxer/chess"
+
+// Setup describes the initial position of a board.
+type Setup struct {
+	// EnPassantSquare is the en passant target square of the initial position.
+	EnPassantSquare chess.Position
+	// HalfmoveClock is the number of halfmoves since the last capture or pawn advance
+	// before the initial position.
+	HalfmoveClock int
+	// MoveNumber is the number of the full move of the initial position, starting at 1.
+	MoveNumber int
+	// Chess960 reports whether the game is Chess960 (Fischer Random chess).
+	// It affects the notation only: castling is always validated by the rules which work for both variants.
+	Chess960 bool
+}
+
+type board interface {
+	Setup() Setup
+}
+
+// Default returns the setup of a game started from scratch.
+func Default() Setup {
+	return Setup{EnPassantSquare: chess.NewPositionEmpty(), MoveNumber: 1}
+}
+
+// FromBoard returns the setup of the board.
+// If the board doesn't provide its setup, the default one is returned.
+func FromBoard(b chess.Board) Setup {
+	if b, ok := b.(board); ok {
+		return b.Setup()
+	}
+
+	return Default()
+}
@@ -0,0 +1,49 @@
+package zobrist
+
+import (
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/move/castling"
+	"github.com/elaxer/standardchess/internal/move/enpassant"
+	"github.com/elaxer/standardchess/internal/move/normal"
+	"github.com/elaxer/standardchess/internal/move/promotion"
+	"github.com/elaxer/standardchess/internal/piece"
+)
+
+// Move returns the combined key of the pieces placement changes made by the move.
+// XORing it with the hash of the position before the move,
+// the turn key and the changes of rights gives the hash of the position after the move.
+// The second return value is false if the move type is unknown.
+func Move(move chess.Move) (uint64, bool) {
+	side := move.Side()
+
+	switch move := move.(type) {
+	case *normal.MoveResult:
+		notation := move.InputMove.PieceNotation
+
+		return Piece(notation, side, move.FromFull) ^ Piece(notation, side, move.InputMove.To) ^
+			captured(move.Captured, move.InputMove.To), true
+	case *promotion.MoveResult:
+		return Piece(piece.NotationPawn, side, move.FromFull) ^
+			Piece(move.InputMove.PromotedPieceNotation, side, move.InputMove.To) ^
+			captured(move.Captured, move.InputMove.To), true
+	case *enpassant.MoveResult:
+		return Piece(piece.NotationPawn, side, move.FromFull) ^
+			Piece(piece.NotationPawn, side, move.InputMove.To) ^
+			captured(move.Captured, chess.NewPosition(move.InputMove.To.File, move.FromFull.Rank)), true
+	case *castling.MoveResult:
+		return Piece(piece.NotationKing, side, move.InitKingPosition) ^
+			Piece(piece.NotationKing, side, move.KingNewPosition()) ^
+			Piece(piece.NotationRook, side, move.InitRookPosition) ^
+			Piece(piece.NotationRook, side, move.RookNewPosition()), true
+	}
+
+	return 0, false
+}
+
+func captured(p chess.Piece, position chess.Position) uint64 {
+	if p == nil {
+		return 0
+	}
+
+	return Piece(p.Notation(), p.Color(), position)
+}
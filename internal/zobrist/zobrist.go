@@ -0,0 +1,140 @@
+// Package zobrist contains the Zobrist hashing of chess positions.
+// The hash of a position is composed of the keys of the placed pieces,
+// the side to move, the castling rights and the file of the en passant target square.
+// The keys are generated by a pseudorandom generator with a fixed seed,
+// so the hashes are stable between program runs.
+package zobrist
+
+import (
+	"strings"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/move/castling"
+	"github.com/elaxer/standardchess/internal/move/enpassant"
+)
+
+const (
+	seed = 0x5EED_CAFE_F00D_BEEF
+
+	pieceSymbols = "PNBRQKpnbrqk"
+	squaresNum   = int(chess.FileMax) * int(chess.RankMax)
+)
+
+var (
+	// Turn is the key of the position where black is to move.
+	Turn uint64
+
+	pieceKeys     [len(pieceSymbols)][squaresNum]uint64
+	castlingKeys  [2][2]uint64
+	enPassantKeys [chess.FileMax]uint64
+)
+
+func init() {
+	state := uint64(seed)
+	for i := range pieceKeys {
+		for j := range pieceKeys[i] {
+			pieceKeys[i][j] = next(&state)
+		}
+	}
+	for i := range castlingKeys {
+		for j := range castlingKeys[i] {
+			castlingKeys[i][j] = next(&state)
+		}
+	}
+	for i := range enPassantKeys {
+		enPassantKeys[i] = next(&state)
+	}
+
+	Turn = next(&state)
+}
+
+// Hash calculates the hash of the current position of the board from scratch.
+func Hash(board chess.Board) uint64 {
+	var hash uint64
+	for position, p := range board.Squares().Iter() {
+		if p != nil {
+			hash ^= Piece(p.Notation(), p.Color(), position)
+		}
+	}
+
+	if board.Turn().IsBlack() {
+		hash ^= Turn
+	}
+
+	return hash ^ Rights(board)
+}
+
+// Piece returns the key of the piece with the notation and color placed on the position.
+// It returns 0 for unknown pieces and positions.
+func Piece(notation string, color chess.Color, position chess.Position) uint64 {
+	symbol := notation
+	if symbol == "" {
+		symbol = "P"
+	}
+	if color.IsBlack() {
+		symbol = strings.ToLower(symbol)
+	}
+
+	i := strings.Index(pieceSymbols, symbol)
+	if i == -1 || len(symbol) != 1 || !position.IsFull() {
+		return 0
+	}
+
+	return pieceKeys[i][(int(position.Rank)-1)*int(chess.FileMax)+int(position.File)-1]
+}
+
+// Castling returns the key of the castling right of the side.
+func Castling(castlingType castling.CastlingType, side chess.Color) uint64 {
+	return castlingKeys[colorIndex(side)][castlingTypeIndex(castlingType)]
+}
+
+// EnPassant returns the key of the en passant target square file.
+func EnPassant(file chess.File) uint64 {
+	if file.IsNull() || file.Validate() != nil {
+		return 0
+	}
+
+	return enPassantKeys[file-1]
+}
+
+// Rights returns the combined key of the castling rights of both sides
+// and the en passant target square, if the side to move can capture en passant.
+func Rights(board chess.Board) uint64 {
+	var hash uint64
+	for _, side := range [...]chess.Color{chess.ColorWhite, chess.ColorBlack} {
+		for _, castlingType := range [...]castling.CastlingType{castling.TypeShort, castling.TypeLong} {
+			if castling.ValidateRight(castlingType, side, board) == nil {
+				hash ^= Castling(castlingType, side)
+			}
+		}
+	}
+
+	return hash ^ EnPassant(enpassant.LegalTargetSquare(board).File)
+}
+
+func colorIndex(color chess.Color) int {
+	if color.IsBlack() {
+		return 1
+	}
+
+	return 0
+}
+
+func castlingTypeIndex(castlingType castling.CastlingType) int {
+	if castlingType.IsLong() {
+		return 1
+	}
+
+	return 0
+}
+
+// next implements the SplitMix64 pseudorandom generator.
+func next(state *uint64) uint64 {
+	*state += 0x9E3779B97F4A7C15
+
+	z := *state
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+
+	return z ^ (z >> 31)
+}
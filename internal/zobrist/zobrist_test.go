@@ -0,0 +1,59 @@
+package zobrist_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/move/castling"
+	"github.com/elaxer/standardchess/internal/piece"
+	"github.com/elaxer/standardchess/internal/standardtest"
+	"github.com/elaxer/standardchess/internal/zobrist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeys_Unique(t *testing.T) {
+	keys := make(map[uint64]bool, 3200)
+	add := func(key uint64) {
+		require.NotZero(t, key)
+		require.False(t, keys[key], "duplicated key")
+
+		keys[key] = true
+	}
+
+	for _, color := range [...]chess.Color{chess.ColorWhite, chess.ColorBlack} {
+		for _, notation := range piece.AllNotations {
+			for rank := chess.RankMin; rank <= chess.RankMax; rank++ {
+				for file := chess.FileMin; file <= chess.FileMax; file++ {
+					add(zobrist.Piece(notation, color, chess.NewPosition(file, rank)))
+				}
+			}
+		}
+
+		add(zobrist.Castling(castling.TypeShort, color))
+		add(zobrist.Castling(castling.TypeLong, color))
+	}
+	for file := chess.FileMin; file <= chess.FileMax; file++ {
+		add(zobrist.EnPassant(file))
+	}
+	add(zobrist.Turn)
+}
+
+func TestPiece_Unknown(t *testing.T) {
+	assert.Zero(t, zobrist.Piece("X", chess.ColorWhite, chess.PositionFromString("e4")))
+	assert.Zero(t, zobrist.Piece(piece.NotationKing, chess.ColorWhite, chess.PositionFromString("e")))
+}
+
+func TestMove(t *testing.T) {
+	board := standardtest.DecodeFEN("r3k2r/1P6/8/3pP3/8/8/8/R3K2R w KQkq d6 0 1")
+	for _, move := range []string{"exd6", "O-O", "b8=Q", "Raxb8", "O-O-O"} {
+		hash, rights := zobrist.Hash(board), zobrist.Rights(board)
+
+		result, err := board.MakeMove(move)
+		require.NoError(t, err, move)
+
+		moveHash, ok := zobrist.Move(result)
+		require.True(t, ok)
+		assert.Equal(t, zobrist.Hash(board), hash^rights^moveHash^zobrist.Turn^zobrist.Rights(board), move)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/elaxer/standardchess/internal/move/enpassant"
 	"github.com/elaxer/standardchess/internal/move/normal"
 	"github.com/elaxer/standardchess/internal/piece"
+	"github.com/elaxer/standardchess/internal/setup"
 )
 
 var AllFuncs = []metric.MetricFunc{
@@ -68,18 +69,23 @@ func EnPassantTargetSquare(board chess.Board) metric.Metric {
 	return metric.New("En passant target square", targetPosition)
 }
 
+// HalfmoveClock calculates the number of halfmoves since the last capture or pawn advance.
+// The counting starts from the halfmove clock of the board setup.
 func HalfmoveClock(board chess.Board) metric.Metric {
-	clock := 0
+	clock := setup.FromBoard(board).HalfmoveClock
 	for _, m := range board.MoveHistory() {
-		normalMove, ok := m.(*normal.MoveResult)
-		if !ok || normalMove.InputMove.PieceNotation == piece.NotationPawn ||
-			normalMove.IsCapture() {
+		switch m := m.(type) {
+		case *castling.MoveResult:
+			clock++
+		case *normal.MoveResult:
+			if m.InputMove.PieceNotation == piece.NotationPawn || m.IsCapture() {
+				clock = 0
+			} else {
+				clock++
+			}
+		default:
 			clock = 0
-
-			continue
 		}
-
-		clock++
 	}
 
 	return metric.New("Halfmove clock", clock)
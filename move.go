@@ -0,0 +1,267 @@
+package standardchess
+
+import (
+	"strings"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/move/castling"
+	"github.com/elaxer/standardchess/internal/move/enpassant"
+	"github.com/elaxer/standardchess/internal/move/normal"
+	"github.com/elaxer/standardchess/internal/move/promotion"
+	"github.com/elaxer/standardchess/internal/piece"
+	"github.com/elaxer/standardchess/internal/setup"
+)
+
+const (
+	CastlingShort = castling.TypeShort
+	CastlingLong  = castling.TypeLong
+)
+
+var promotionNotations = [...]string{
+	piece.NotationQueen,
+	piece.NotationRook,
+	piece.NotationBishop,
+	piece.NotationKnight,
+}
+
+// CastlingType is the type of castling: short (O-O) or long (O-O-O).
+type CastlingType = castling.CastlingType
+
+// Move describes a legal move available on a board.
+type Move struct {
+	// From is the initial position of the moving piece. For castling it's the initial position of the king.
+	From chess.Position
+	// To is the destination of the moving piece. For castling it's the destination of the king.
+	To chess.Position
+	// Piece is the moving piece. For castling it's the king.
+	Piece chess.Piece
+	// CapturedPiece is the piece captured by the move, nil if there is no capture.
+	CapturedPiece chess.Piece
+	// PromotedPieceNotation is the notation of the piece a pawn is promoted to,
+	// empty if the move is not a promotion.
+	PromotedPieceNotation string
+	// IsEnPassant reports whether the move is an en passant capture.
+	IsEnPassant bool
+	// IsCastling reports whether the move is castling.
+	IsCastling bool
+	// CastlingType is the type of castling. It makes sense only if IsCastling is true.
+	CastlingType CastlingType
+
+	san string
+	// rookFrom is the initial position of the castling rook.
+	rookFrom chess.Position
+	// isChess960 reports whether the move is made on a Chess960 board.
+	isChess960 bool
+}
+
+// MoveList returns all legal moves available for the side to move.
+// Each move is made on the board and undone to find out its SAN,
+// so the list must not be requested while the board is used concurrently.
+// The moves are listed in the positions drawn by the rules too, they are made by MakeMovePastDraw.
+// The list is empty in checkmate and stalemate.
+func MoveList(board chess.Board) []Move {
+	pseudoMoves := pseudoMoves(board)
+	moves := make([]Move, 0, len(pseudoMoves))
+	for _, move := range pseudoMoves {
+		result, err := MakeMovePastDraw(board, move.input())
+		if err != nil {
+			continue
+		}
+
+		move.san = result.String()
+		moves = append(moves, move)
+
+		_, err = board.UndoLastMove()
+		must(err)
+	}
+
+	return moves
+}
+
+// MakeMovePastDraw makes the move on the board like MakeMove, but only checkmate and stalemate end the game:
+// the moves are made in the positions drawn by the rules too, e.g. by a threefold repetition nobody has claimed.
+// Boards of other packages are moved by MakeMove.
+func MakeMovePastDraw(chessBoard chess.Board, move string) (chess.Move, error) {
+	b, ok := chessBoard.(*board)
+	if !ok {
+		return chessBoard.MakeMove(move)
+	}
+	if state := b.State(); state == StateCheckmate || state == StateStalemate {
+		return nil, ErrCannotMoveInTerminalState
+	}
+
+	result, err := b.makeMove(move)
+	if err != nil {
+		return nil, err
+	}
+	result.SetBoardNewState(b.State())
+
+	return result, nil
+}
+
+// SAN returns the move in the Standard Algebraic Notation with the check or checkmate suffix,
+// disambiguated by the file or rank of the initial position if needed, e.g. "Nbd7", "exd8=Q#" or "O-O".
+func (m Move) SAN() string {
+	return m.san
+}
+
+// UCI returns the move in the long algebraic notation used by the UCI protocol,
+// e.g. "g1f3", "e7e8q" or "e1g1" for castling.
+// Castling on Chess960 boards is written as the king capturing its own rook, e.g. "e1h1".
+func (m Move) UCI() string {
+	if m.IsCastling && m.isChess960 {
+		return m.From.String() + m.rookFrom.String()
+	}
+
+	return m.From.String() + m.To.String() + strings.ToLower(m.PromotedPieceNotation)
+}
+
+// CastlingRook returns the initial position of the castling rook, an empty position if the move isn't castling.
+func (m Move) CastlingRook() chess.Position {
+	return m.rookFrom
+}
+
+// IsCapture reports whether the move captures a piece.
+func (m Move) IsCapture() bool {
+	return m.CapturedPiece != nil
+}
+
+// IsPromotion reports whether the move is a pawn promotion.
+func (m Move) IsPromotion() bool {
+	return m.PromotedPieceNotation != ""
+}
+
+func (m Move) String() string {
+	return m.SAN()
+}
+
+// input returns the move in the notation accepted by MakeMove
+// with the full initial position, so that it doesn't need to be resolved.
+func (m Move) input() string {
+	if m.IsCastling {
+		return m.CastlingType.String()
+	}
+	if m.IsPromotion() {
+		return m.From.String() + m.To.String() + "=" + m.PromotedPieceNotation
+	}
+
+	return m.Piece.Notation() + m.From.String() + m.To.String()
+}
+
+// PseudoMoveList returns the moves available for the side to move without making them on the board.
+// Unlike MoveList, the moves have no SAN and a few of them may be rejected by MakeMove,
+// e.g. an en passant capture exposing the king, so it's much faster.
+// It suits move generation of searches which make the moves anyway.
+func PseudoMoveList(board chess.Board) []Move {
+	if board.State().Type().IsTerminal() {
+		return []Move{}
+	}
+
+	return pseudoMoves(board)
+}
+
+// pseudoMoves works like PseudoMoveList but doesn't check the state of the board,
+// so the moves of the drawn positions are returned too.
+func pseudoMoves(board chess.Board) []Move {
+	pieces := make([]chess.Piece, 0, 16)
+	for p := range board.Squares().GetAllPieces(board.Turn()) {
+		pieces = append(pieces, p)
+	}
+
+	moves := make([]Move, 0, 64)
+	enPassantPosition := enpassant.EnPassantTargetSquare(board)
+	for _, p := range pieces {
+		from := board.Squares().GetByPiece(p)
+		for _, to := range board.LegalMoves(p) {
+			move := Move{From: from, To: to, Piece: p}
+			move.CapturedPiece, _ = board.Squares().FindByPosition(to)
+
+			if p.Notation() != piece.NotationPawn {
+				moves = append(moves, move)
+
+				continue
+			}
+
+			if to == enPassantPosition && move.CapturedPiece == nil {
+				move.IsEnPassant = true
+				move.CapturedPiece, _ = board.Squares().FindByPosition(
+					chess.NewPosition(to.File, from.Rank),
+				)
+			}
+
+			if !isPromotionRank(to.Rank, p.Color(), board.Squares()) {
+				moves = append(moves, move)
+
+				continue
+			}
+
+			for _, notation := range promotionNotations {
+				move.PromotedPieceNotation = notation
+				moves = append(moves, move)
+			}
+		}
+	}
+
+	king, kingPosition := board.Squares().FindPiece(piece.NotationKing, board.Turn())
+	isChess960 := setup.FromBoard(board).Chess960
+	for _, castlingType := range [...]CastlingType{CastlingShort, CastlingLong} {
+		if castling.ValidateMove(castlingType, board) != nil {
+			continue
+		}
+
+		rookPosition, _ := castling.RookPosition(castlingType, board.Turn(), board)
+		kingNewPosition, _ := castling.CastledPositions(castlingType, kingPosition.Rank)
+		moves = append(moves, Move{
+			From:         kingPosition,
+			To:           kingNewPosition,
+			Piece:        king,
+			IsCastling:   true,
+			CastlingType: castlingType,
+			rookFrom:     rookPosition,
+			isChess960:   isChess960,
+		})
+	}
+
+	return moves
+}
+
+// MoveHistoryUCI returns the moves made on the board in the UCI notation, see Move.UCI.
+func MoveHistoryUCI(board chess.Board) []string {
+	isChess960 := setup.FromBoard(board).Chess960
+
+	moves := make([]string, 0, len(board.MoveHistory()))
+	for _, move := range board.MoveHistory() {
+		moves = append(moves, resultUCI(move, isChess960))
+	}
+
+	return moves
+}
+
+// resultUCI returns the made move in the UCI notation.
+func resultUCI(result chess.Move, isChess960 bool) string {
+	switch result := result.(type) {
+	case *normal.MoveResult:
+		return result.FromFull.String() + result.InputMove.To.String()
+	case *enpassant.MoveResult:
+		return result.FromFull.String() + result.InputMove.To.String()
+	case *promotion.MoveResult:
+		return result.FromFull.String() + result.InputMove.To.String() +
+			strings.ToLower(result.InputMove.PromotedPieceNotation)
+	case *castling.MoveResult:
+		if isChess960 {
+			return result.InitKingPosition.String() + result.InitRookPosition.String()
+		}
+
+		return result.InitKingPosition.String() + result.KingNewPosition().String()
+	default:
+		return result.Input()
+	}
+}
+
+func isPromotionRank(rank chess.Rank, color chess.Color, squares *chess.Squares) bool {
+	if color.IsBlack() {
+		return rank == chess.RankMin
+	}
+
+	return rank == squares.EdgePosition().Rank
+}
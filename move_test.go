@@ -0,0 +1,185 @@
+package standardchess_test
+
+import (
+	"testing"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/internal/standardtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveList(t *testing.T) {
+	tests := []struct {
+		name     string
+		fen      string
+		wantSAN  []string
+		wantUCI  []string
+		wantSize int
+	}{
+		{
+			"init_position",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			[]string{"a3", "a4", "e4", "Na3", "Nc3", "Nf3", "Nh3"},
+			[]string{"a2a3", "e2e4", "b1a3", "g1f3"},
+			20,
+		},
+		{
+			"disambiguation",
+			"4k3/8/8/8/1N3N2/8/8/R3K2R w KQ - 0 1",
+			[]string{"Nbd3", "Nfd3", "Nbd5", "Rd1", "Rf1", "O-O", "O-O-O", "Ra8+"},
+			[]string{"b4d3", "f4d3", "e1g1", "e1c1", "a1a8"},
+			40,
+		},
+		{
+			"promotions",
+			"3r2k1/4P3/8/8/8/8/8/4K3 w - - 0 1",
+			[]string{"exd8=Q+", "exd8=R+", "exd8=B", "exd8=N", "e8=Q+", "e8=R+", "e8=B", "e8=N"},
+			[]string{"e7d8q", "e7d8n", "e7e8q", "e7e8b"},
+			11,
+		},
+		{
+			"en_passant",
+			"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 2",
+			[]string{"exd6", "e6", "Kd2"},
+			[]string{"e5d6", "e5e6"},
+			7,
+		},
+		{
+			"checkmate",
+			"6k1/5ppp/8/8/8/8/8/R3K3 w Q - 0 1",
+			[]string{"Ra8#", "O-O-O"},
+			[]string{"a1a8", "e1c1"},
+			16,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := standardtest.DecodeFEN(tt.fen)
+			moves := standardchess.MoveList(board)
+
+			sans := make([]string, 0, len(moves))
+			ucis := make([]string, 0, len(moves))
+			for _, move := range moves {
+				sans = append(sans, move.SAN())
+				ucis = append(ucis, move.UCI())
+			}
+
+			assert.Len(t, moves, tt.wantSize)
+			assert.Subset(t, sans, tt.wantSAN)
+			assert.Subset(t, ucis, tt.wantUCI)
+		})
+	}
+}
+
+func TestMoveList_Fields(t *testing.T) {
+	board := standardtest.DecodeFEN("r3k3/1P6/8/3pP3/8/8/8/4K3 w q d6 0 2")
+
+	moves := make(map[string]standardchess.Move)
+	for _, move := range standardchess.MoveList(board) {
+		moves[move.SAN()] = move
+	}
+
+	enPassant := moves["exd6"]
+	assert.True(t, enPassant.IsEnPassant)
+	assert.True(t, enPassant.IsCapture())
+	assert.Equal(t, "p", enPassant.CapturedPiece.String())
+
+	promotion := moves["bxa8=N"]
+	assert.True(t, promotion.IsPromotion())
+	assert.Equal(t, standardchess.NotationKnight, promotion.PromotedPieceNotation)
+	assert.Equal(t, "r", promotion.CapturedPiece.String())
+	assert.Equal(t, "P", promotion.Piece.String())
+
+	board = standardtest.DecodeFEN("r3k3/8/8/8/8/8/8/4K3 b q - 0 1")
+	moves = make(map[string]standardchess.Move)
+	for _, move := range standardchess.MoveList(board) {
+		moves[move.SAN()] = move
+	}
+
+	castling := moves["O-O-O"]
+	assert.True(t, castling.IsCastling)
+	assert.Equal(t, standardchess.CastlingLong, castling.CastlingType)
+	assert.Equal(t, "e8c8", castling.UCI())
+}
+
+func TestMoveList_BoardIsRestored(t *testing.T) {
+	board, err := standardchess.NewBoardFromMoves([]string{"e4", "e5", "Nf3"})
+	require.NoError(t, err)
+
+	hash := board.Hash()
+	moves := standardchess.MoveList(board)
+	require.NotEmpty(t, moves)
+
+	assert.Equal(t, hash, board.Hash())
+	assert.Len(t, board.MoveHistory(), 3)
+
+	for _, move := range moves {
+		_, err := board.MakeMove(move.SAN())
+		require.NoError(t, err, move.SAN())
+		_, err = board.UndoLastMove()
+		require.NoError(t, err)
+	}
+}
+
+func TestMoveList_TerminalState(t *testing.T) {
+	board := standardtest.DecodeFEN("R5k1/5ppp/8/8/8/8/8/4K3 b - - 0 1")
+	assert.Empty(t, standardchess.MoveList(board))
+}
+
+func TestMoveList_Draw(t *testing.T) {
+	board, err := standardchess.NewBoardFromMoves([]string{"Nf3", "Nf6", "Ng1", "Ng8", "Nf3", "Nf6", "Ng1", "Ng8"})
+	require.NoError(t, err)
+	require.Equal(t, standardchess.StateThreefoldRepetition, board.State())
+
+	assert.Len(t, standardchess.MoveList(board), 20)
+}
+
+func TestMakeMovePastDraw(t *testing.T) {
+	board, err := standardchess.NewBoardFromMoves([]string{"Nf3", "Nf6", "Ng1", "Ng8", "Nf3", "Nf6", "Ng1", "Ng8"})
+	require.NoError(t, err)
+
+	_, err = board.MakeMove("e4")
+	require.ErrorIs(t, err, standardchess.ErrCannotMoveInTerminalState)
+
+	move, err := standardchess.MakeMovePastDraw(board, "e4")
+	require.NoError(t, err)
+	assert.Equal(t, "e4", move.String())
+	assert.Equal(t, standardchess.StateClear, board.State())
+}
+
+func TestMakeMovePastDraw_Checkmate(t *testing.T) {
+	board := standardtest.DecodeFEN("R5k1/5ppp/8/8/8/8/8/4K3 b - - 0 1")
+
+	_, err := standardchess.MakeMovePastDraw(board, "h6")
+	assert.ErrorIs(t, err, standardchess.ErrCannotMoveInTerminalState)
+}
+
+func TestMoveHistoryUCI(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		moves []string
+		want  []string
+	}{
+		{
+			"standard",
+			"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			[]string{"e4", "d5", "e5", "f5", "exf6", "Nc6", "Nf3", "Bf5", "Bc4", "Qd7", "O-O", "O-O-O"},
+			[]string{"e2e4", "d7d5", "e4e5", "f7f5", "e5f6", "b8c6", "g1f3", "c8f5", "f1c4", "d8d7", "e1g1", "e8c8"},
+		},
+		{"promotion", "4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", []string{"b8=Q+", "Ke7"}, []string{"b7b8q", "e8e7"}},
+		{"chess960", "4k3/8/8/8/8/8/8/1RK5 w Q - 0 1", []string{"O-O-O"}, []string{"c1b1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := mustDecodeFEN(t, tt.fen)
+			for _, move := range tt.moves {
+				_, err := board.MakeMove(move)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, standardchess.MoveHistoryUCI(board))
+		})
+	}
+}
@@ -0,0 +1,347 @@
+// Package openingtree builds the opening trees of game collections:
+// the moves played in each position with the number of games, their results and the average ratings.
+// The positions are identified by the Polyglot keys, so the transpositions are merged,
+// and a tree can be exported as a Polyglot opening book.
+package openingtree
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/elaxer/standardchess/encoding/polyglot"
+)
+
+// errMoveNotFound is returned if a move made on the board isn't among its legal moves which mustn't happen.
+var errMoveNotFound = errors.New("the move isn't found in the legal moves")
+
+// Stats are the statistics of the games which reached a position or played a move.
+type Stats struct {
+	Games     int
+	WhiteWins int
+	Draws     int
+	BlackWins int
+
+	whiteElo rating
+	blackElo rating
+}
+
+// MoveStats are the statistics of the games which played the move.
+type MoveStats struct {
+	Stats
+
+	Move standardchess.Move
+}
+
+// Tree is the opening tree of games.
+type Tree struct {
+	maxPlies int
+	nodes    map[uint64]*node
+}
+
+// rating is the sum and the number of the known ratings of the players.
+type rating struct {
+	sum   int
+	count int
+}
+
+// node is a position of the tree.
+type node struct {
+	Stats
+
+	turn  chess.Color
+	moves []*MoveStats
+}
+
+// ply is a move of a game added to the tree.
+type ply struct {
+	key  uint64
+	turn chess.Color
+	move standardchess.Move
+}
+
+// New creates an empty tree which records the first maxPlies plies of the games.
+func New(maxPlies int) *Tree {
+	return &Tree{maxPlies: maxPlies, nodes: make(map[uint64]*node)}
+}
+
+// Build builds the tree of the games read from r, recording their first maxPlies plies.
+// The games which can't be parsed or added are skipped, their errors are joined and returned with the tree.
+func Build(r io.Reader, maxPlies int) (*Tree, error) {
+	tree := New(maxPlies)
+
+	var errs []error
+	i := 0
+	for game, err := range pgn.Parse(r) {
+		i++
+		if err == nil {
+			err = tree.Add(game)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("game %d: %w", i, err))
+		}
+	}
+
+	return tree, errors.Join(errs...)
+}
+
+// Add adds the main line of the game to the tree, the game starts from the position of its headers, see pgn.NewBoard.
+// The tree isn't changed if an error is returned:
+// pgn.ErrIllegalMove if a move can't be made or polyglot.ErrUnsupportedBoard if the board isn't 8x8.
+func (t *Tree) Add(game pgn.PGN) error {
+	board, err := pgn.NewBoard(game.Headers())
+	if err != nil {
+		return err
+	}
+	if board.Squares().EdgePosition() != standardchess.EdgePosition {
+		return polyglot.ErrUnsupportedBoard
+	}
+
+	sans := game.Moves()
+	sans = sans[:min(len(sans), t.maxPlies)]
+	plies := make([]ply, 0, len(sans))
+	for i, san := range sans {
+		key, turn := polyglot.Key(board), board.Turn()
+		move, err := t.makeMove(board, key, san)
+		if err != nil {
+			return fmt.Errorf("%w: ply %d, %s: %w", pgn.ErrIllegalMove, i+1, san, err)
+		}
+
+		plies = append(plies, ply{key, turn, move})
+	}
+
+	t.addStats(plies, polyglot.Key(board), board.Turn(), gameStats(game))
+
+	return nil
+}
+
+// Position returns the statistics of the games which reached the position on the board.
+// The second value is false if there are no such games.
+func (t *Tree) Position(board chess.Board) (Stats, bool) {
+	node, ok := t.find(board)
+	if !ok {
+		return Stats{}, false
+	}
+
+	return node.Stats, true
+}
+
+// Moves returns the moves played in the position on the board sorted by the number of games in descending order.
+func (t *Tree) Moves(board chess.Board) []MoveStats {
+	node, ok := t.find(board)
+	if !ok {
+		return []MoveStats{}
+	}
+
+	moves := make([]MoveStats, 0, len(node.moves))
+	for _, move := range node.moves {
+		moves = append(moves, *move)
+	}
+	slices.SortStableFunc(moves, func(a, b MoveStats) int {
+		return b.Games - a.Games
+	})
+
+	return moves
+}
+
+// Len returns the number of positions in the tree.
+func (t *Tree) Len() int {
+	return len(t.nodes)
+}
+
+// WritePolyglot writes the tree as a Polyglot opening book.
+// The weight of a move is the score of the side which made it: 2 points for a win and 1 point for a draw.
+// The weights of a position are scaled down proportionally if they don't fit into 16 bits.
+func (t *Tree) WritePolyglot(w io.Writer) error {
+	entries := make([]polyglot.Entry, 0, len(t.nodes))
+	for key, node := range t.nodes {
+		scores := make([]int, 0, len(node.moves))
+		for _, move := range node.moves {
+			scores = append(scores, move.score(node.turn))
+		}
+
+		maxScore := 0
+		if len(scores) > 0 {
+			maxScore = slices.Max(scores)
+		}
+		for i, move := range node.moves {
+			weight := scores[i]
+			if maxScore > math.MaxUint16 {
+				weight = weight * math.MaxUint16 / maxScore
+			}
+
+			entries = append(entries, polyglot.Entry{
+				Key:    key,
+				Move:   polyglot.EncodeMove(move.Move),
+				Weight: uint16(weight),
+			})
+		}
+	}
+
+	return polyglot.Write(w, entries)
+}
+
+// makeMove makes the move on the board and returns it.
+// The move is looked up in the legal moves of the position only if it's new to the tree.
+func (t *Tree) makeMove(board standardchess.Board, key uint64, san string) (standardchess.Move, error) {
+	result, err := standardchess.MakeMovePastDraw(board, san)
+	if err != nil {
+		return standardchess.Move{}, err
+	}
+
+	if node, ok := t.nodes[key]; ok {
+		for _, move := range node.moves {
+			if move.Move.SAN() == result.String() {
+				return move.Move, nil
+			}
+		}
+	}
+
+	if _, err := board.UndoLastMove(); err != nil {
+		return standardchess.Move{}, err
+	}
+	legalMoves := standardchess.MoveList(board)
+	if _, err := standardchess.MakeMovePastDraw(board, san); err != nil {
+		return standardchess.Move{}, err
+	}
+
+	i := slices.IndexFunc(legalMoves, func(move standardchess.Move) bool {
+		return move.SAN() == result.String()
+	})
+	if i < 0 {
+		return standardchess.Move{}, errMoveNotFound
+	}
+
+	return legalMoves[i], nil
+}
+
+// addStats adds the statistics of the game to the positions and the moves of its plies and to its final position.
+// A position or a move repeated in the game, e.g. after 1. Nf3 Nf6 2. Ng1 Ng8, is counted once.
+func (t *Tree) addStats(plies []ply, key uint64, turn chess.Color, stats Stats) {
+	nodes := make(map[*node]bool, len(plies)+1)
+	moves := make(map[*MoveStats]bool, len(plies))
+	for _, p := range plies {
+		node := t.node(p.key, p.turn)
+		if !nodes[node] {
+			nodes[node] = true
+			node.add(stats)
+		}
+		if moveStats := node.move(p.move); !moves[moveStats] {
+			moves[moveStats] = true
+			moveStats.add(stats)
+		}
+	}
+
+	if node := t.node(key, turn); !nodes[node] {
+		node.add(stats)
+	}
+}
+
+func (t *Tree) node(key uint64, turn chess.Color) *node {
+	n, ok := t.nodes[key]
+	if !ok {
+		n = &node{turn: turn}
+		t.nodes[key] = n
+	}
+
+	return n
+}
+
+func (t *Tree) find(board chess.Board) (*node, bool) {
+	if board.Squares().EdgePosition() != standardchess.EdgePosition {
+		return nil, false
+	}
+
+	n, ok := t.nodes[polyglot.Key(board)]
+
+	return n, ok
+}
+
+// AverageWhiteElo returns the average rating of the white players, 0 if their ratings are unknown.
+func (s Stats) AverageWhiteElo() int {
+	return s.whiteElo.average()
+}
+
+// AverageBlackElo returns the average rating of the black players, 0 if their ratings are unknown.
+func (s Stats) AverageBlackElo() int {
+	return s.blackElo.average()
+}
+
+func (s *Stats) add(other Stats) {
+	s.Games += other.Games
+	s.WhiteWins += other.WhiteWins
+	s.Draws += other.Draws
+	s.BlackWins += other.BlackWins
+	s.whiteElo.add(other.whiteElo)
+	s.blackElo.add(other.blackElo)
+}
+
+// score returns the score of the side: 2 points for a win and 1 point for a draw.
+func (s Stats) score(side chess.Color) int {
+	if side.IsWhite() {
+		return 2*s.WhiteWins + s.Draws
+	}
+
+	return 2*s.BlackWins + s.Draws
+}
+
+func (r rating) average() int {
+	if r.count == 0 {
+		return 0
+	}
+
+	return r.sum / r.count
+}
+
+func (r *rating) add(other rating) {
+	r.sum += other.sum
+	r.count += other.count
+}
+
+// move returns the statistics of the move, they are created if the move hasn't been played in the position yet.
+func (n *node) move(move standardchess.Move) *MoveStats {
+	for _, moveStats := range n.moves {
+		if moveStats.Move.SAN() == move.SAN() {
+			return moveStats
+		}
+	}
+
+	moveStats := &MoveStats{Move: move}
+	n.moves = append(n.moves, moveStats)
+
+	return moveStats
+}
+
+// gameStats returns the statistics of the single game.
+func gameStats(game pgn.PGN) Stats {
+	stats := Stats{Games: 1}
+	switch result := game.Result(); {
+	case result.IsWinWhite():
+		stats.WhiteWins++
+	case result.IsDraw():
+		stats.Draws++
+	case result.IsWinBlack():
+		stats.BlackWins++
+	}
+
+	stats.whiteElo = parseRating(game.Headers().Value(pgn.HeaderWhiteElo))
+	stats.blackElo = parseRating(game.Headers().Value(pgn.HeaderBlackElo))
+
+	return stats
+}
+
+// parseRating returns the rating of the header value, the rating is unknown if the value isn't a positive number.
+func parseRating(value string) rating {
+	elo, err := strconv.Atoi(value)
+	if err != nil || elo <= 0 {
+		return rating{}
+	}
+
+	return rating{sum: elo, count: 1}
+}
@@ -0,0 +1,207 @@
+package openingtree_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/elaxer/standardchess/encoding/polyglot"
+	"github.com/elaxer/standardchess/openingtree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const games = `[Event "1"]
+[WhiteElo "2000"]
+[BlackElo "1800"]
+
+1. d4 d5 2. Nf3 Nf6 1-0
+
+[Event "2"]
+[WhiteElo "2200"]
+[BlackElo "2400"]
+
+1. Nf3 d5 2. d4 e6 0-1
+
+[Event "3"]
+[WhiteElo "?"]
+
+1. d4 Nf6 2. c4 e6 1/2-1/2
+
+[Event "4"]
+
+1. e4 e5 2. Nf3 Nc6 3. Bc4 Bc5 4. O-O *
+`
+
+func TestBuild(t *testing.T) {
+	tree, err := openingtree.Build(strings.NewReader(games), 4)
+	require.NoError(t, err)
+
+	board := standardchess.NewBoard()
+	stats, ok := tree.Position(board)
+	require.True(t, ok)
+	assert.Equal(t, 4, stats.Games)
+	assert.Equal(t, 1, stats.WhiteWins)
+	assert.Equal(t, 1, stats.Draws)
+	assert.Equal(t, 1, stats.BlackWins)
+	assert.Equal(t, 2100, stats.AverageWhiteElo())
+	assert.Equal(t, 2100, stats.AverageBlackElo())
+
+	moves := tree.Moves(board)
+	require.Len(t, moves, 3)
+	assert.Equal(t, "d4", moves[0].Move.SAN())
+	assert.Equal(t, 2, moves[0].Games)
+	assert.Equal(t, 2000, moves[0].AverageWhiteElo())
+	assert.Equal(t, "Nf3", moves[1].Move.SAN())
+	assert.Equal(t, "e4", moves[2].Move.SAN())
+	assert.Zero(t, moves[2].AverageWhiteElo())
+}
+
+func TestBuild_Transposition(t *testing.T) {
+	tree, err := openingtree.Build(strings.NewReader(games), 4)
+	require.NoError(t, err)
+
+	board, err := standardchess.NewBoardFromMoves([]string{"Nf3", "d5", "d4"})
+	require.NoError(t, err)
+
+	stats, ok := tree.Position(board)
+	require.True(t, ok)
+	assert.Equal(t, 2, stats.Games)
+	assert.Equal(t, 1, stats.WhiteWins)
+	assert.Equal(t, 1, stats.BlackWins)
+
+	moves := tree.Moves(board)
+	require.Len(t, moves, 2)
+	assert.Equal(t, "Nf6", moves[0].Move.SAN())
+	assert.Equal(t, "e6", moves[1].Move.SAN())
+}
+
+func TestTree_Add_Repetition(t *testing.T) {
+	game, err := pgn.FromString("1. Nf3 Nf6 2. Ng1 Ng8 3. Nf3 Nf6 4. Ng1 Ng8 5. e4 1-0")
+	require.NoError(t, err)
+
+	tree := openingtree.New(20)
+	require.NoError(t, tree.Add(game))
+
+	stats, ok := tree.Position(standardchess.NewBoard())
+	require.True(t, ok)
+	assert.Equal(t, 1, stats.Games)
+	assert.Equal(t, 1, stats.WhiteWins)
+
+	moves := tree.Moves(standardchess.NewBoard())
+	require.Len(t, moves, 2)
+	assert.Equal(t, 1, moves[0].Games)
+	assert.Equal(t, 1, moves[1].Games)
+}
+
+func TestBuild_MaxPlies(t *testing.T) {
+	tree, err := openingtree.Build(strings.NewReader(games), 2)
+	require.NoError(t, err)
+
+	board, err := standardchess.NewBoardFromMoves([]string{"d4", "d5"})
+	require.NoError(t, err)
+
+	stats, ok := tree.Position(board)
+	require.True(t, ok)
+	assert.Equal(t, 1, stats.Games)
+	assert.Empty(t, tree.Moves(board))
+
+	_, err = board.MakeMove("Nf3")
+	require.NoError(t, err)
+
+	_, ok = tree.Position(board)
+	assert.False(t, ok)
+}
+
+func TestBuild_Errors(t *testing.T) {
+	tree, err := openingtree.Build(strings.NewReader(`[Event "1"]
+
+1. e4 e5 2. Ke3 *
+
+[Event "2"]
+
+1. e4 c5 *
+
+[Event "3"]
+
+1. d4 d5 2. Nf3 ) *
+
+[Event "4"]
+
+1. d4 d5 *
+`), 10)
+	require.ErrorIs(t, err, pgn.ErrIllegalMove)
+	assert.Contains(t, err.Error(), "game 1: ")
+	assert.Contains(t, err.Error(), "game 3: ")
+
+	board, err := standardchess.NewBoardFromMoves([]string{"e4"})
+	require.NoError(t, err)
+
+	moves := tree.Moves(board)
+	require.Len(t, moves, 1)
+	assert.Equal(t, "c5", moves[0].Move.SAN())
+
+	stats, ok := tree.Position(standardchess.NewBoard())
+	require.True(t, ok)
+	assert.Equal(t, 2, stats.Games)
+}
+
+func TestTree_Add_FENHeader(t *testing.T) {
+	game, err := pgn.FromString(`[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/8/R3K3 w Q - 0 1"]
+
+1. O-O-O Kf7 1/2-1/2`)
+	require.NoError(t, err)
+
+	tree := openingtree.New(10)
+	require.NoError(t, tree.Add(game))
+	assert.Equal(t, 3, tree.Len())
+
+	board, err := pgn.NewBoard(game.Headers())
+	require.NoError(t, err)
+
+	moves := tree.Moves(board)
+	require.Len(t, moves, 1)
+	assert.Equal(t, "O-O-O", moves[0].Move.SAN())
+	assert.Equal(t, 1, moves[0].Draws)
+}
+
+func TestTree_WritePolyglot(t *testing.T) {
+	tree, err := openingtree.Build(strings.NewReader(games), 8)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, tree.WritePolyglot(&buf))
+
+	book, err := polyglot.NewBook(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	board := standardchess.NewBoard()
+	moves, err := book.Moves(board)
+	require.NoError(t, err)
+	require.Len(t, moves, 3)
+	assert.Equal(t, "d4", moves[0].Move.SAN())
+	assert.Equal(t, 3, moves[0].Weight)
+	assert.Equal(t, "Nf3", moves[1].Move.SAN())
+	assert.Zero(t, moves[1].Weight)
+	assert.Equal(t, "e4", moves[2].Move.SAN())
+	assert.Zero(t, moves[2].Weight)
+
+	board, err = standardchess.NewBoardFromMoves([]string{"Nf3", "d5", "d4"})
+	require.NoError(t, err)
+
+	move, ok, err := book.BestMove(board)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "e6", move.SAN())
+
+	board, err = standardchess.NewBoardFromMoves([]string{"e4", "e5", "Nf3", "Nc6", "Bc4", "Bc5"})
+	require.NoError(t, err)
+
+	move, ok, err = book.BestMove(board)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "O-O", move.SAN())
+}
@@ -0,0 +1,65 @@
+package standardchess
+
+import "github.com/elaxer/chess"
+
+// Perft walks the tree of legal moves to the depth and returns the number of its leaf nodes.
+// Every move is made and undone on the board, so the board must not be used concurrently.
+// As in the standard perft, only checkmate and stalemate end the walk: the moves are made
+// in the positions drawn by the rules too, e.g. by the threefold repetition or insufficient material.
+// Boards of other packages are walked by MakeMove, so their draws have no moves.
+func Perft(board chess.Board, depth int) int {
+	if depth <= 0 {
+		return 1
+	}
+
+	nodes := 0
+	for _, move := range pseudoMoves(board) {
+		if !makePerftMove(board, move) {
+			continue
+		}
+
+		nodes += Perft(board, depth-1)
+
+		_, err := board.UndoLastMove()
+		must(err)
+	}
+
+	return nodes
+}
+
+// PerftDivide works like Perft but returns the number of leaf nodes
+// for each legal move of the side to move separately.
+// The moves are keyed by the UCI notation.
+func PerftDivide(board chess.Board, depth int) map[string]int {
+	divide := make(map[string]int)
+	if depth <= 0 {
+		return divide
+	}
+
+	for _, move := range pseudoMoves(board) {
+		if !makePerftMove(board, move) {
+			continue
+		}
+
+		divide[move.UCI()] = Perft(board, depth-1)
+
+		_, err := board.UndoLastMove()
+		must(err)
+	}
+
+	return divide
+}
+
+// makePerftMove makes the move on the board bypassing the check of the state of the board if it's possible.
+// It reports whether the move is legal.
+func makePerftMove(chessBoard chess.Board, move Move) bool {
+	if b, ok := chessBoard.(*board); ok {
+		_, err := b.makeMove(move.input())
+
+		return err == nil
+	}
+
+	_, err := chessBoard.MakeMove(move.input())
+
+	return err == nil
+}
@@ -0,0 +1,125 @@
+package standardchess_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/elaxer/standardchess"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var backends = []standardchess.Backend{standardchess.BackendSquares, standardchess.BackendBitboard}
+
+// shortPerftNodes is the number of leaf nodes of the perft runs made in the short mode
+// and defaultPerftNodes is the one of the runs made by default.
+// The deeper runs take minutes and are made only if the deepPerftEnv environment variable is set.
+// squaresPerftNodes limits the runs on the squares backend, which is much slower than the bitboard one.
+const (
+	shortPerftNodes   = 100_000
+	defaultPerftNodes = 500_000
+	squaresPerftNodes = 20_000
+)
+
+const deepPerftEnv = "STANDARDCHESS_PERFT_DEEP"
+
+// Positions and node counts are taken from https://www.chessprogramming.org/Perft_Results
+// and https://www.chessprogramming.org/Chess960_Perft_Results
+// and from the perft suite by Martin Sedlak.
+func TestPerft(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		depth int
+		want  int
+	}{
+		{"init_position", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", 3, 8902},
+		{"init_position", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", 4, 197281},
+		{"init_position", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", 5, 4865609},
+		{"kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 2, 2039},
+		{"kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 3, 97862},
+		{"kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 4, 4085603},
+		{"position_3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 3, 2812},
+		{"position_3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 4, 43238},
+		{"position_3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 5, 674624},
+		{"position_4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 3, 9467},
+		{"position_4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 4, 422333},
+		{"position_4_mirrored", "r2q1rk1/pP1p2pp/Q4n2/bbp1p3/Np6/1B3NBn/pPPP1PPP/R3K2R b KQ - 0 1", 3, 9467},
+		{"position_5", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", 3, 62379},
+		{"position_6", "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10", 3, 89890},
+
+		{"en_passant_discovered_check", "3k4/3p4/8/K1P4r/8/8/8/8 b - - 0 1", 6, 1134888},
+		{"en_passant_gives_check", "8/8/4k3/8/2p5/8/B2P2K1/8 w - - 0 1", 6, 1015133},
+		{"en_passant_pinned", "8/8/1k6/2b5/2pP4/8/5K2/8 b - d3 0 1", 6, 1440467},
+		{"en_passant_capturing_checker", "8/8/8/2k5/2pP4/8/B7/4K3 b - d3 0 3", 1, 8},
+		{"en_passant_two_pawns", "r3k2r/8/8/8/3pPp2/8/8/R3K1RR b KQkq e3 0 1", 2, 829},
+		{"short_castling_gives_check", "5k2/8/8/8/8/8/8/4K2R w K - 0 1", 6, 661072},
+		{"long_castling_gives_check", "3k4/8/8/8/8/8/8/R3K3 w Q - 0 1", 6, 803711},
+		{"castling_rights", "r3k2r/1b4bq/8/8/8/8/7B/R3K2R w KQkq - 0 1", 4, 1274206},
+		{"castling_prevented", "r3k2r/8/3Q4/8/8/5q2/8/R3K2R b KQkq - 0 1", 4, 1720476},
+		{"castling_through_pawn_attack", "4k3/8/8/8/8/8/4p3/4K2R w K - 0 1", 1, 12},
+		{"promotion_out_of_check", "2K2r2/4P3/8/8/8/8/8/3k4 w - - 0 1", 6, 3821001},
+		{"discovered_check", "8/8/1P2K3/8/2n5/1q6/8/5k2 b - - 0 1", 5, 1004658},
+		{"promotion_gives_check", "4k3/1P6/8/8/8/8/K7/8 w - - 0 1", 6, 217342},
+		{"underpromotion_gives_check", "8/P1k5/K7/8/8/8/8/8 w - - 0 1", 6, 92683},
+		{"self_stalemate", "K1k5/8/P7/8/8/8/8/8 w - - 0 1", 6, 2217},
+		{"stalemate_and_checkmate", "8/k1P5/8/1K6/8/8/8/8 w - - 0 1", 7, 567584},
+		{"double_check", "8/8/2k5/5q2/5n2/8/5K2/8 b - - 0 1", 4, 23527},
+		{"promotions", "n1n5/PPPk4/8/8/8/8/4Kppp/5N1N b - - 0 1", 3, 9483},
+		{"promotions", "n1n5/PPPk4/8/8/8/8/4Kppp/5N1N b - - 0 1", 5, 3605103},
+
+		{"chess960_1", "bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9", 3, 12189},
+		{"chess960_1", "bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9", 4, 326672},
+		{"chess960_2", "2nnrbkr/p1qppppp/8/1ppb4/6PP/3PP3/PPP2P2/BQNNRBKR w HEhe - 1 9", 3, 18002},
+		{"chess960_2", "2nnrbkr/p1qppppp/8/1ppb4/6PP/3PP3/PPP2P2/BQNNRBKR w HEhe - 1 9", 4, 667366},
+		{"chess960_3", "b1q1rrkb/pppppppp/3nn3/8/P7/1PPP4/4PPPP/BQNNRKRB w GE - 1 9", 3, 10471},
+		{"chess960_3", "b1q1rrkb/pppppppp/3nn3/8/P7/1PPP4/4PPPP/BQNNRKRB w GE - 1 9", 4, 273318},
+	}
+	for _, backend := range backends {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/%s/%d", backend, tt.name, tt.depth), func(t *testing.T) {
+				if backend == standardchess.BackendSquares && tt.want > squaresPerftNodes {
+					t.Skip("too slow on the squares backend")
+				}
+				if testing.Short() && tt.want > shortPerftNodes {
+					t.Skip("too slow for the short mode")
+				}
+				if tt.want > defaultPerftNodes && os.Getenv(deepPerftEnv) == "" {
+					t.Skip("too slow, set " + deepPerftEnv + " to run it")
+				}
+
+				board := mustDecodeFEN(t, tt.fen)
+				require.NoError(t, standardchess.SetBackend(board, backend))
+				hash := board.Hash()
+
+				assert.Equal(t, tt.want, standardchess.Perft(board, tt.depth))
+				assert.Equal(t, hash, board.Hash())
+				assert.Empty(t, board.MoveHistory())
+			})
+		}
+	}
+}
+
+func TestPerft_Depth0(t *testing.T) {
+	assert.Equal(t, 1, standardchess.Perft(standardchess.NewBoard(), 0))
+	assert.Empty(t, standardchess.PerftDivide(standardchess.NewBoard(), 0))
+}
+
+func TestPerftDivide(t *testing.T) {
+	board := mustDecodeFEN(t, "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1")
+
+	divide := standardchess.PerftDivide(board, 2)
+
+	assert.Len(t, divide, 48)
+	assert.Equal(t, 43, divide["e1g1"])
+	assert.Equal(t, 43, divide["e1c1"])
+	assert.Equal(t, 46, divide["d5e6"])
+	assert.Equal(t, 44, divide["e5f7"])
+
+	total := 0
+	for _, nodes := range divide {
+		total += nodes
+	}
+	assert.Equal(t, standardchess.Perft(board, 2), total)
+}
@@ -31,7 +31,7 @@ func TestBoardPlayer_GoTo(t *testing.T) {
 		{
 			27,
 			true,
-			"r1b1k2r/ppp3pp/8/Q5B1/3P4/2P5/PP3PPP/R3KB1R b KQk - 0 14",
+			"r1b1k2r/ppp3pp/8/Q5B1/3P4/2P5/PP3PPP/R3KB1R b KQkq - 0 14",
 		},
 		{
 			47, // last
@@ -2,6 +2,7 @@ package standardchess
 
 import (
 	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess/internal/rule"
 	"github.com/elaxer/standardchess/internal/state"
 )
 
@@ -26,3 +27,9 @@ var (
 	// (e.g., king vs king, king and bishop vs king).
 	StateInsufficientMaterial = state.InsufficientMaterial
 )
+
+// CanCheckmate reports whether the side could checkmate the opponent by any series of legal moves,
+// e.g. a game lost on time is drawn if the opponent can't checkmate.
+func CanCheckmate(board chess.Board, color chess.Color) bool {
+	return rule.CanCheckmate(board, color)
+}
@@ -0,0 +1,244 @@
+// Package uci drives external chess engines speaking the Universal Chess Interface protocol
+// over a pair of io.Reader and io.Writer or as a subprocess.
+package uci
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/internal/protocol"
+)
+
+var (
+	ErrEngineExited = protocol.ErrEngineExited
+	ErrNoMoves      = errors.New("there are no legal moves")
+	ErrIllegalMove  = errors.New("illegal move")
+)
+
+// Engine is an external UCI engine.
+// An engine must not be used concurrently.
+type Engine struct {
+	// Progress is called with each info line of the search if it's set.
+	Progress func(Info)
+
+	name    string
+	author  string
+	options []Option
+
+	conn *protocol.Conn
+}
+
+// Result is the result of the search.
+type Result struct {
+	// BestMove is the best move found.
+	BestMove standardchess.Move
+	// PV is the principal variation starting from BestMove, the ponder move of the engine is the second one.
+	PV []standardchess.Move
+	// Score is the last score of the principal variation from the point of view of the side to move.
+	Score Score
+	// Depth is the depth of the last principal variation.
+	Depth int
+	// Nodes is the last number of the searched positions reported by the engine.
+	Nodes int
+	// Duration is the time spent on the search reported by the engine.
+	Duration time.Duration
+}
+
+// NewEngine creates the engine reading its output from r and writing the commands to w.
+// The engine must be initialized by Init.
+func NewEngine(r io.Reader, w io.Writer) *Engine {
+	return &Engine{conn: protocol.NewConn(r, w, 0)}
+}
+
+// Start starts the engine subprocess with the arguments.
+// The engine must be initialized by Init and closed after use.
+func Start(name string, args ...string) (*Engine, error) {
+	conn, err := protocol.Start(0, name, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Engine{conn: conn}, nil
+}
+
+// Init makes the handshake: the engine is switched to the UCI mode and reports its name, author and options.
+func (e *Engine) Init(ctx context.Context) error {
+	if err := e.conn.Send("uci"); err != nil {
+		return err
+	}
+
+	for {
+		line, err := e.conn.ReadLine(ctx)
+		if err != nil {
+			return err
+		}
+
+		command, args := protocol.CutCommand(line)
+		switch command {
+		case "id":
+			key, value := protocol.CutCommand(args)
+			switch key {
+			case "name":
+				e.name = value
+			case "author":
+				e.author = value
+			}
+		case "option":
+			e.options = append(e.options, parseOption(args))
+		case "uciok":
+			return nil
+		}
+	}
+}
+
+// Name returns the name of the engine reported by Init.
+func (e *Engine) Name() string {
+	return e.name
+}
+
+// Author returns the author of the engine reported by Init.
+func (e *Engine) Author() string {
+	return e.author
+}
+
+// Options returns the options of the engine reported by Init.
+func (e *Engine) Options() []Option {
+	return slices.Clone(e.options)
+}
+
+// SetOption sets the value of the option, the value of a button option is empty.
+// IsReady should be called then to wait for the engine to apply the option.
+func (e *Engine) SetOption(name, value string) error {
+	if value == "" {
+		return e.conn.Send("setoption name " + name)
+	}
+
+	return e.conn.Send("setoption name " + name + " value " + value)
+}
+
+// IsReady waits for the engine to be ready for the commands.
+func (e *Engine) IsReady(ctx context.Context) error {
+	if err := e.conn.Send("isready"); err != nil {
+		return err
+	}
+
+	for {
+		line, err := e.conn.ReadLine(ctx)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(line) == "readyok" {
+			return nil
+		}
+	}
+}
+
+// NewGame tells the engine that the next search is from a different game and waits for it to be ready.
+func (e *Engine) NewGame(ctx context.Context) error {
+	if err := e.conn.Send("ucinewgame"); err != nil {
+		return err
+	}
+
+	return e.IsReady(ctx)
+}
+
+// Position sets the position of the board: the position the game is started from and the moves made on the board.
+func (e *Engine) Position(board chess.Board) error {
+	initial, err := fen.EncodeInitial(board)
+	if err != nil {
+		return err
+	}
+
+	command := "position fen " + initial.String()
+	if moves := standardchess.MoveHistoryUCI(board); len(moves) > 0 {
+		command += " moves " + strings.Join(moves, " ")
+	}
+
+	return e.conn.Send(command)
+}
+
+// Search sets the position of the board and searches the best move within the limits.
+// The search is stopped when the context is done, the best move found by then is returned.
+// ErrNoMoves is returned if the engine reports no best move.
+func (e *Engine) Search(ctx context.Context, board chess.Board, limits Limits) (Result, error) {
+	if err := e.Position(board); err != nil {
+		return Result{}, err
+	}
+	if err := e.conn.Send(limits.command()); err != nil {
+		return Result{}, err
+	}
+
+	var last Info
+	for {
+		line, err := e.conn.ReadLine(ctx)
+		if err != nil && ctx.Err() != nil {
+			if err := e.conn.Send("stop"); err != nil {
+				return Result{}, err
+			}
+			// The engine must answer the stop command with the best move.
+			ctx = context.WithoutCancel(ctx)
+
+			continue
+		}
+		if err != nil {
+			return Result{}, err
+		}
+
+		command, args := protocol.CutCommand(line)
+		switch command {
+		case "info":
+			info := ParseInfo(args)
+			if e.Progress != nil {
+				e.Progress(info)
+			}
+			if len(info.PV) > 0 {
+				last = info
+			}
+		case "bestmove":
+			return result(board, args, last)
+		}
+	}
+}
+
+// Close quits the engine.
+// The engine subprocess is killed if it doesn't quit in time.
+func (e *Engine) Close() error {
+	return e.conn.Close("quit")
+}
+
+// result returns the result of the bestmove command arguments with the last principal variation.
+func result(board chess.Board, args string, last Info) (Result, error) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || fields[0] == "(none)" || fields[0] == "0000" {
+		return Result{}, ErrNoMoves
+	}
+
+	line := last.PV
+	if len(line) == 0 || line[0] != fields[0] {
+		line = []string{fields[0]}
+		if len(fields) == 3 && fields[1] == "ponder" {
+			line = append(line, fields[2])
+		}
+	}
+
+	pv, err := Moves(board, line)
+	if len(pv) == 0 {
+		return Result{}, err
+	}
+
+	return Result{
+		BestMove: pv[0],
+		PV:       pv,
+		Score:    last.Score,
+		Depth:    last.Depth,
+		Nodes:    last.Nodes,
+		Duration: last.Time,
+	}, nil
+}
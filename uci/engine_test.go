@@ -0,0 +1,271 @@
+package uci_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/uci"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedEngineArg makes the test binary run the scripted engine instead of the tests.
+const scriptedEngineArg = "scripted-uci-engine"
+
+// scriptedEngine is a stand-in engine answering the commands by the script.
+type scriptedEngine struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == scriptedEngineArg {
+		new(scriptedEngine).run(os.Stdin, os.Stdout, defaultScript)
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestEngine_Init(t *testing.T) {
+	engine, _ := newScriptedEngine(t, defaultScript)
+
+	require.NoError(t, engine.Init(context.Background()))
+	assert.Equal(t, "Scripted 1.0", engine.Name())
+	assert.Equal(t, "The Testers", engine.Author())
+	assert.Equal(t, []uci.Option{
+		{Name: "Hash", Type: uci.OptionSpin, Default: "16", Min: 1, Max: 1024},
+		{Name: "Skill Level", Type: uci.OptionCombo, Default: "Normal", Vars: []string{"Weak", "Normal", "Very Strong"}},
+		{Name: "Ponder", Type: uci.OptionCheck, Default: "false"},
+		{Name: "Clear Hash", Type: uci.OptionButton},
+		{Name: "Book File", Type: uci.OptionString},
+	}, engine.Options())
+}
+
+func TestEngine_Commands(t *testing.T) {
+	engine, script := newScriptedEngine(t, defaultScript)
+	ctx := context.Background()
+
+	require.NoError(t, engine.Init(ctx))
+	require.NoError(t, engine.SetOption("Hash", "64"))
+	require.NoError(t, engine.SetOption("Clear Hash", ""))
+	require.NoError(t, engine.IsReady(ctx))
+	require.NoError(t, engine.NewGame(ctx))
+
+	board, err := standardchess.NewBoardFromMoves([]string{"e4", "e5", "Nf3", "Nc6", "Bc4", "Bc5", "O-O"})
+	require.NoError(t, err)
+	require.NoError(t, engine.Position(board))
+	require.NoError(t, engine.IsReady(ctx))
+
+	assert.Equal(t, []string{
+		"uci",
+		"setoption name Hash value 64",
+		"setoption name Clear Hash",
+		"isready",
+		"ucinewgame",
+		"isready",
+		"position fen rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1 " +
+			"moves e2e4 e7e5 g1f3 b8c6 f1c4 f8c5 e1g1",
+		"isready",
+	}, script.received())
+}
+
+func TestEngine_Search(t *testing.T) {
+	engine, script := newScriptedEngine(t, defaultScript)
+
+	var infos []uci.Info
+	engine.Progress = func(info uci.Info) {
+		infos = append(infos, info)
+	}
+
+	board, err := fen.Decode("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	require.NoError(t, err)
+
+	result, err := engine.Search(context.Background(), board, uci.Limits{
+		Depth:          2,
+		WhiteTime:      time.Minute,
+		BlackTime:      50 * time.Second,
+		WhiteIncrement: time.Second,
+		MovesToGo:      20,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "e4", result.BestMove.SAN())
+	require.Len(t, result.PV, 2)
+	assert.Equal(t, "e5", result.PV[1].SAN())
+	assert.Equal(t, uci.Score{Centipawns: 35}, result.Score)
+	assert.Equal(t, 2, result.Depth)
+	assert.Equal(t, 120, result.Nodes)
+	assert.Equal(t, 15*time.Millisecond, result.Duration)
+
+	require.Len(t, infos, 3)
+	assert.Equal(t, "e2e4", infos[1].CurrMove)
+	assert.Equal(t, []string{"e2e4", "e7e5"}, infos[2].PV)
+
+	commands := script.received()
+	assert.Equal(t, "go wtime 60000 btime 50000 winc 1000 movestogo 20 depth 2", commands[len(commands)-1])
+	assert.Empty(t, board.MoveHistory())
+}
+
+func TestEngine_Search_Stop(t *testing.T) {
+	engine, script := newScriptedEngine(t, defaultScript)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine.Progress = func(uci.Info) {
+		cancel()
+	}
+
+	board, err := standardchess.NewBoardFromMoves([]string{"e4"})
+	require.NoError(t, err)
+
+	result, err := engine.Search(ctx, board, uci.Limits{Infinite: true, SearchMoves: []string{"c7c5", "e7e5"}})
+	require.NoError(t, err)
+	assert.Equal(t, "c5", result.BestMove.SAN())
+	assert.Equal(t, uci.Score{Mate: -3, UpperBound: true}, result.Score)
+
+	commands := script.received()
+	assert.Equal(t, []string{"go searchmoves c7c5 e7e5 infinite", "stop"}, commands[len(commands)-2:])
+}
+
+func TestEngine_Search_NoMoves(t *testing.T) {
+	engine, _ := newScriptedEngine(t, func(command string, w io.Writer) bool {
+		if strings.HasPrefix(command, "go") {
+			fmt.Fprintln(w, "info string checkmated")
+			fmt.Fprintln(w, "bestmove (none)")
+
+			return true
+		}
+
+		return defaultScript(command, w)
+	})
+
+	board, err := fen.Decode("R5k1/5ppp/8/8/8/8/8/4K3 b - - 0 1")
+	require.NoError(t, err)
+
+	_, err = engine.Search(context.Background(), board, uci.Limits{Depth: 1})
+	assert.ErrorIs(t, err, uci.ErrNoMoves)
+}
+
+func TestEngine_Exited(t *testing.T) {
+	engine, _ := newScriptedEngine(t, func(command string, w io.Writer) bool {
+		return command != "uci"
+	})
+
+	err := engine.Init(context.Background())
+	assert.ErrorIs(t, err, uci.ErrEngineExited)
+}
+
+func TestEngine_Init_Context(t *testing.T) {
+	engine, _ := newScriptedEngine(t, func(string, io.Writer) bool {
+		return true
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, engine.Init(ctx), context.DeadlineExceeded)
+}
+
+func TestStart(t *testing.T) {
+	engine, err := uci.Start(os.Args[0], scriptedEngineArg)
+	require.NoError(t, err)
+
+	require.NoError(t, engine.Init(context.Background()))
+	assert.Equal(t, "Scripted 1.0", engine.Name())
+
+	result, err := engine.Search(context.Background(), standardchess.NewBoard(), uci.Limits{MoveTime: time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, "e4", result.BestMove.SAN())
+
+	require.NoError(t, engine.Close())
+	require.NoError(t, engine.Close())
+}
+
+// defaultScript answers the commands of the tests.
+func defaultScript(command string, w io.Writer) bool {
+	switch {
+	case command == "uci":
+		fmt.Fprint(w, `id name Scripted 1.0
+id author The Testers
+option name Hash type spin default 16 min 1 max 1024
+option name Skill Level type combo default Normal var Weak var Normal var Very Strong
+option name Ponder type check default false
+option name Clear Hash type button
+option name Book File type string default <empty>
+uciok
+`)
+	case command == "isready":
+		fmt.Fprintln(w, "readyok")
+	case strings.HasPrefix(command, "go") && strings.Contains(command, "infinite"):
+		fmt.Fprintln(w, "info depth 30 score mate -3 upperbound pv c7c5 g1f3")
+	case command == "stop":
+		fmt.Fprintln(w, "bestmove c7c5 ponder g1f3")
+	case strings.HasPrefix(command, "go"):
+		fmt.Fprint(w, `info string searching
+info depth 1 currmove e2e4 currmovenumber 1
+info depth 2 seldepth 3 multipv 1 score cp 35 nodes 120 nps 8000 time 15 hashfull 1 tbhits 0 pv e2e4 e7e5
+bestmove e2e4 ponder e7e5
+`)
+	case command == "quit":
+		return false
+	}
+
+	return true
+}
+
+// newScriptedEngine runs the scripted engine connected to the engine by the pipes.
+// The script returns false to make the scripted engine exit.
+func newScriptedEngine(t *testing.T, script func(command string, w io.Writer) bool) (*uci.Engine, *scriptedEngine) {
+	t.Helper()
+
+	commandsReader, commandsWriter := io.Pipe()
+	outputReader, outputWriter := io.Pipe()
+	t.Cleanup(func() {
+		_ = commandsWriter.Close()
+		_ = outputWriter.Close()
+	})
+
+	scripted := new(scriptedEngine)
+	go func() {
+		scripted.run(commandsReader, outputWriter, script)
+		_ = outputWriter.Close()
+		_, _ = io.Copy(io.Discard, commandsReader)
+	}()
+
+	engine := uci.NewEngine(outputReader, commandsWriter)
+	t.Cleanup(func() {
+		_ = engine.Close()
+	})
+
+	return engine, scripted
+}
+
+func (s *scriptedEngine) run(r io.Reader, w io.Writer, script func(command string, w io.Writer) bool) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		s.mu.Lock()
+		s.commands = append(s.commands, scanner.Text())
+		s.mu.Unlock()
+
+		if !script(scanner.Text(), w) {
+			return
+		}
+	}
+}
+
+func (s *scriptedEngine) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.commands...)
+}
@@ -0,0 +1,227 @@
+package uci
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// infoKeys are the keys of the info command, the values of a key are the following words up to the next key.
+var infoKeys = map[string]bool{
+	"depth":          true,
+	"seldepth":       true,
+	"time":           true,
+	"nodes":          true,
+	"pv":             true,
+	"multipv":        true,
+	"score":          true,
+	"currmove":       true,
+	"currmovenumber": true,
+	"hashfull":       true,
+	"nps":            true,
+	"tbhits":         true,
+	"sbhits":         true,
+	"cpuload":        true,
+	"string":         true,
+	"refutation":     true,
+	"currline":       true,
+}
+
+// Limits bound the search. Zero values mean no limit, the engine decides when to stop if there are no limits.
+// The search is also stopped when its context is done.
+type Limits struct {
+	// Depth is the maximum depth of the search in plies.
+	Depth int
+	// Nodes is the maximum number of the searched positions.
+	Nodes int
+	// Mate is the number of moves to search a mate in.
+	Mate int
+	// MoveTime is the exact duration of the search.
+	MoveTime time.Duration
+	// WhiteTime and BlackTime are the remaining times on the clocks of the players.
+	WhiteTime, BlackTime time.Duration
+	// WhiteIncrement and BlackIncrement are the increments of the players per move.
+	WhiteIncrement, BlackIncrement time.Duration
+	// MovesToGo is the number of moves to the next time control.
+	MovesToGo int
+	// Infinite makes the engine search until it's stopped by the context.
+	Infinite bool
+	// SearchMoves restricts the search to the moves in the UCI notation.
+	SearchMoves []string
+}
+
+// Info is the information about the search sent by the engine.
+// The fields which aren't sent are zero.
+type Info struct {
+	// Depth is the depth of the search in plies.
+	Depth int
+	// SelDepth is the selective depth of the search in plies.
+	SelDepth int
+	// MultiPV is the number of the principal variation in the multi PV mode.
+	MultiPV int
+	// Score is the evaluation of the position from the point of view of the side to move.
+	Score Score
+	// Nodes is the number of the searched positions.
+	Nodes int
+	// NPS is the number of the searched positions per second.
+	NPS int
+	// Time is the time spent on the search.
+	Time time.Duration
+	// HashFull is the occupancy of the hash table in permills.
+	HashFull int
+	// TBHits is the number of the positions found in the endgame tablebases.
+	TBHits int
+	// CurrMove is the move searched at the moment in the UCI notation.
+	CurrMove string
+	// CurrMoveNumber is the number of the move searched at the moment starting from 1.
+	CurrMoveNumber int
+	// PV is the principal variation in the UCI notation.
+	PV []string
+	// String is the text sent by the engine.
+	String string
+}
+
+// Score is the evaluation of a position.
+type Score struct {
+	// Centipawns is the evaluation in centipawns if it's not a mate.
+	Centipawns int
+	// Mate is the number of moves to the mate, it's negative if the engine is mated and zero if it's not a mate.
+	Mate int
+	// LowerBound and UpperBound report whether the score is only a bound of the evaluation.
+	LowerBound, UpperBound bool
+}
+
+// ParseInfo parses the arguments of the info command, e.g. "depth 12 score cp 31 nodes 4000 pv e2e4 e7e5".
+// The unknown keys and the malformed values are ignored.
+func ParseInfo(args string) Info {
+	var info Info
+
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); {
+		key := fields[i]
+		if key == "string" {
+			info.String = strings.Join(fields[i+1:], " ")
+
+			break
+		}
+
+		end := i + 1
+		for end < len(fields) && !infoKeys[fields[end]] {
+			end++
+		}
+		info.set(key, fields[i+1:end])
+		i = end
+	}
+
+	return info
+}
+
+// IsMate reports whether the score is a mate.
+func (s Score) IsMate() bool {
+	return s.Mate != 0
+}
+
+func (s Score) String() string {
+	if s.IsMate() {
+		return "mate " + strconv.Itoa(s.Mate)
+	}
+
+	return "cp " + strconv.Itoa(s.Centipawns)
+}
+
+func (i *Info) set(key string, values []string) {
+	number := 0
+	if len(values) > 0 {
+		number, _ = strconv.Atoi(values[0])
+	}
+
+	switch key {
+	case "depth":
+		i.Depth = number
+	case "seldepth":
+		i.SelDepth = number
+	case "multipv":
+		i.MultiPV = number
+	case "score":
+		i.Score = parseScore(values)
+	case "nodes":
+		i.Nodes = number
+	case "nps":
+		i.NPS = number
+	case "time":
+		i.Time = time.Duration(number) * time.Millisecond
+	case "hashfull":
+		i.HashFull = number
+	case "tbhits":
+		i.TBHits = number
+	case "currmove":
+		if len(values) > 0 {
+			i.CurrMove = values[0]
+		}
+	case "currmovenumber":
+		i.CurrMoveNumber = number
+	case "pv":
+		i.PV = values
+	}
+}
+
+// command returns the go command with the limits.
+func (l Limits) command() string {
+	words := []string{"go"}
+	if len(l.SearchMoves) > 0 {
+		words = append(words, "searchmoves")
+		words = append(words, l.SearchMoves...)
+	}
+
+	for _, limit := range [...]struct {
+		name  string
+		value int
+	}{
+		{"wtime", int(l.WhiteTime.Milliseconds())},
+		{"btime", int(l.BlackTime.Milliseconds())},
+		{"winc", int(l.WhiteIncrement.Milliseconds())},
+		{"binc", int(l.BlackIncrement.Milliseconds())},
+		{"movestogo", l.MovesToGo},
+		{"depth", l.Depth},
+		{"nodes", l.Nodes},
+		{"mate", l.Mate},
+		{"movetime", int(l.MoveTime.Milliseconds())},
+	} {
+		if limit.value > 0 {
+			words = append(words, limit.name, strconv.Itoa(limit.value))
+		}
+	}
+
+	if l.Infinite {
+		words = append(words, "infinite")
+	}
+
+	return strings.Join(words, " ")
+}
+
+// parseScore parses the values of the score key, e.g. "cp 31", "mate -3" or "cp 20 lowerbound".
+func parseScore(values []string) Score {
+	var score Score
+	for i := 0; i < len(values); i++ {
+		switch values[i] {
+		case "cp", "mate":
+			if i+1 == len(values) {
+				break
+			}
+
+			number, _ := strconv.Atoi(values[i+1])
+			if values[i] == "cp" {
+				score.Centipawns = number
+			} else {
+				score.Mate = number
+			}
+			i++
+		case "lowerbound":
+			score.LowerBound = true
+		case "upperbound":
+			score.UpperBound = true
+		}
+	}
+
+	return score
+}
@@ -0,0 +1,70 @@
+package uci
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+)
+
+// Moves returns the legal moves of the line in the UCI notation, e.g. a principal variation,
+// made one by one starting from the position on the board. The board itself isn't changed.
+// ErrIllegalMove is returned with the moves before the illegal one.
+func Moves(board chess.Board, line []string) ([]standardchess.Move, error) {
+	position, err := copyPosition(board)
+	if err != nil {
+		return nil, err
+	}
+
+	moves := make([]standardchess.Move, 0, len(line))
+	for _, uci := range line {
+		legalMoves := standardchess.MoveList(position)
+		i := slices.IndexFunc(legalMoves, func(move standardchess.Move) bool {
+			return move.UCI() == uci
+		})
+		if i < 0 {
+			return moves, fmt.Errorf("%w: %s", ErrIllegalMove, uci)
+		}
+		if _, err := position.MakeMove(legalMoves[i].SAN()); err != nil {
+			return moves, fmt.Errorf("%w: %s: %w", ErrIllegalMove, uci, err)
+		}
+
+		moves = append(moves, legalMoves[i])
+	}
+
+	return moves, nil
+}
+
+// SAN returns the moves of the line in the UCI notation in SAN,
+// the moves are made one by one starting from the position on the board. The board itself isn't changed.
+// ErrIllegalMove is returned with the moves before the illegal one.
+func SAN(board chess.Board, line []string) ([]string, error) {
+	position, err := copyPosition(board)
+	if err != nil {
+		return nil, err
+	}
+
+	sans := make([]string, 0, len(line))
+	for _, uci := range line {
+		result, err := position.MakeMove(uci)
+		if err != nil {
+			return sans, fmt.Errorf("%w: %s: %w", ErrIllegalMove, uci, err)
+		}
+
+		sans = append(sans, result.String())
+	}
+
+	return sans, nil
+}
+
+// copyPosition returns a new board with the position of the board.
+func copyPosition(board chess.Board) (standardchess.Board, error) {
+	f := fen.Encode(board).String()
+	if b, ok := board.(standardchess.Board); ok && b.Setup().Chess960 {
+		return fen.DecodeChess960(f)
+	}
+
+	return fen.Decode(f)
+}
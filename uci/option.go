@@ -0,0 +1,78 @@
+package uci
+
+import (
+	"strconv"
+	"strings"
+)
+
+// The types of the engine options.
+const (
+	OptionCheck  OptionType = "check"
+	OptionSpin   OptionType = "spin"
+	OptionCombo  OptionType = "combo"
+	OptionButton OptionType = "button"
+	OptionString OptionType = "string"
+)
+
+// emptyValue is the value of a string option which is empty.
+const emptyValue = "<empty>"
+
+// optionKeys are the keys of the option command, the values of a key are the following words up to the next key.
+var optionKeys = map[string]bool{
+	"name":    true,
+	"type":    true,
+	"default": true,
+	"min":     true,
+	"max":     true,
+	"var":     true,
+}
+
+// OptionType is the type of an engine option.
+type OptionType string
+
+// Option is an option of the engine which can be changed by Engine.SetOption.
+type Option struct {
+	Name string
+	Type OptionType
+	// Default is the default value of the option.
+	Default string
+	// Min and Max bound the value of a spin option.
+	Min, Max int
+	// Vars are the allowed values of a combo option.
+	Vars []string
+}
+
+// parseOption parses the arguments of the option command,
+// e.g. "name Hash type spin default 16 min 1 max 1024".
+func parseOption(args string) Option {
+	var option Option
+
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); {
+		end := i + 1
+		for end < len(fields) && !optionKeys[fields[end]] {
+			end++
+		}
+
+		value := strings.Join(fields[i+1:end], " ")
+		switch fields[i] {
+		case "name":
+			option.Name = value
+		case "type":
+			option.Type = OptionType(value)
+		case "default":
+			if value != emptyValue {
+				option.Default = value
+			}
+		case "min":
+			option.Min, _ = strconv.Atoi(value)
+		case "max":
+			option.Max, _ = strconv.Atoi(value)
+		case "var":
+			option.Vars = append(option.Vars, value)
+		}
+		i = end
+	}
+
+	return option
+}
@@ -0,0 +1,399 @@
+// Package xboard implements the Chess Engine Communication Protocol used by XBoard and WinBoard.
+// Engine drives an external engine speaking the protocol and Server runs the built-in engine behind it.
+// The commands of the protocol are mapped onto a standardchess board.
+package xboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/elaxer/standardchess/internal/protocol"
+)
+
+const (
+	// featureTimeout is the time the engine is given to send its features.
+	// Engines of the protocol version 1 don't send them at all.
+	featureTimeout = 2 * time.Second
+	// outputLines is the number of the lines of the engine output buffered while the commands are sent,
+	// e.g. the features answered one by one.
+	outputLines = 64
+)
+
+var (
+	ErrEngineExited = protocol.ErrEngineExited
+	ErrIllegalMove  = errors.New("illegal move")
+	ErrResigned     = errors.New("the engine has resigned")
+	ErrGameOver     = errors.New("the engine has claimed the result")
+)
+
+// acceptedFeatures are the features of the engine which are taken into account.
+var acceptedFeatures = map[string]bool{
+	"myname":   true,
+	"usermove": true,
+	"san":      true,
+	"setboard": true,
+	"ping":     true,
+	"time":     true,
+	"reuse":    true,
+	"colors":   true,
+	"sigint":   true,
+	"sigterm":  true,
+	"variants": true,
+	"done":     true,
+}
+
+// Engine is an external engine speaking the Chess Engine Communication Protocol.
+// The engine keeps the board of the game in sync with the board of the external engine.
+// An engine must not be used concurrently.
+type Engine struct {
+	// Progress is called with each thinking output line of the engine if it's set.
+	Progress func(Thinking)
+
+	features map[string]string
+	board    standardchess.Board
+	ping     int
+
+	conn *protocol.Conn
+}
+
+// feature is a feature of the protocol sent by the engine.
+type feature struct {
+	name, value string
+}
+
+// Limits bound the thinking of the engine. Zero values aren't sent to the engine.
+type Limits struct {
+	// Time and OpponentTime are the remaining times on the clocks of the engine and its opponent.
+	Time, OpponentTime time.Duration
+	// MoveTime is the exact time of the move.
+	MoveTime time.Duration
+	// Depth is the maximum depth of the search in plies.
+	Depth int
+}
+
+// Result is the move of the engine.
+type Result struct {
+	// BestMove is the move made by the engine.
+	BestMove standardchess.Move
+	// Thinking is the last thinking output of the engine, it's zero if the engine doesn't send it.
+	Thinking Thinking
+}
+
+// NewEngine creates the engine reading its output from r and writing the commands to w.
+// The engine must be initialized by Init.
+func NewEngine(r io.Reader, w io.Writer) *Engine {
+	return newEngine(protocol.NewConn(r, w, outputLines))
+}
+
+// Start starts the engine subprocess with the arguments.
+// The engine must be initialized by Init and closed after use.
+func Start(name string, args ...string) (*Engine, error) {
+	conn, err := protocol.Start(outputLines, name, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEngine(conn), nil
+}
+
+// Init switches the engine to the xboard mode and negotiates the features of the protocol version 2.
+// The features of the engines of the protocol version 1 are waited for 2 seconds.
+func (e *Engine) Init(ctx context.Context) error {
+	if err := e.conn.Send("xboard"); err != nil {
+		return err
+	}
+	if err := e.conn.Send("protover 2"); err != nil {
+		return err
+	}
+
+	featureCtx, cancel := context.WithTimeout(ctx, featureTimeout)
+	defer cancel()
+
+	for {
+		line, err := e.conn.ReadLine(featureCtx)
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		command, args := protocol.CutCommand(line)
+		if command != "feature" {
+			continue
+		}
+
+		for _, f := range parseFeatures(args) {
+			if err := e.accept(f.name, f.value); err != nil {
+				return err
+			}
+
+			if f.name == "done" && f.value == "0" {
+				// The engine needs more time to start.
+				featureCtx = ctx
+			} else if f.name == "done" {
+				return nil
+			}
+		}
+	}
+}
+
+// Name returns the name of the engine reported by Init.
+func (e *Engine) Name() string {
+	return e.features["myname"]
+}
+
+// Feature returns the value of the feature accepted by Init.
+func (e *Engine) Feature(name string) (string, bool) {
+	value, ok := e.features[name]
+
+	return value, ok
+}
+
+// Board returns the board of the game played by the engine.
+// The board must not be changed, the moves are made by UserMove and Go.
+func (e *Engine) Board() standardchess.Board {
+	return e.board
+}
+
+// NewGame starts a new game from the initial position. The engine is put into the force mode,
+// so it doesn't think until Go is called.
+func (e *Engine) NewGame() error {
+	e.board = standardchess.NewBoard()
+	if err := e.conn.Send("new"); err != nil {
+		return err
+	}
+
+	return e.conn.Send("force")
+}
+
+// SetBoard sets the position of the game by the FEN.
+// The engine must support the setboard feature.
+func (e *Engine) SetBoard(f string) error {
+	board, err := fen.Decode(f)
+	if err != nil {
+		return err
+	}
+	if err := e.conn.Send("setboard " + f); err != nil {
+		return err
+	}
+	e.board = board
+
+	return nil
+}
+
+// UserMove makes the move of the opponent in SAN or UCI notation.
+func (e *Engine) UserMove(move string) error {
+	made, err := makeMove(e.board, move)
+	if err != nil {
+		return err
+	}
+
+	notation := made.UCI()
+	if e.features["san"] == "1" {
+		notation = made.SAN()
+	}
+	if e.features["usermove"] == "1" {
+		notation = "usermove " + notation
+	}
+
+	return e.conn.Send(notation)
+}
+
+// Undo takes back the last move of the game.
+func (e *Engine) Undo() error {
+	if _, err := e.board.UndoLastMove(); err != nil {
+		return err
+	}
+
+	return e.conn.Send("undo")
+}
+
+// Go makes the engine think within the limits and make the move of the side to move.
+// The engine is asked to move now when the context is done.
+// ErrResigned or ErrGameOver is returned if the engine resigns or claims the result instead of the move.
+func (e *Engine) Go(ctx context.Context, limits Limits) (Result, error) {
+	if err := e.sendLimits(limits); err != nil {
+		return Result{}, err
+	}
+	if err := e.conn.Send("go"); err != nil {
+		return Result{}, err
+	}
+
+	var last Thinking
+	for {
+		line, err := e.conn.ReadLine(ctx)
+		if err != nil && ctx.Err() != nil {
+			if err := e.conn.Send("?"); err != nil {
+				return Result{}, err
+			}
+			// The engine must answer the move now command with the move.
+			ctx = context.WithoutCancel(ctx)
+
+			continue
+		}
+		if err != nil {
+			return Result{}, err
+		}
+
+		if thinking, ok := ParseThinking(line); ok {
+			if e.Progress != nil {
+				e.Progress(thinking)
+			}
+			last = thinking
+
+			continue
+		}
+		if err := engineError(line); err != nil {
+			return Result{}, err
+		}
+
+		if command, args := protocol.CutCommand(line); command == "move" {
+			return e.move(args, last)
+		}
+	}
+}
+
+// Ping waits for the engine to process all the commands sent before.
+// It returns at once if the engine doesn't support the ping feature.
+func (e *Engine) Ping(ctx context.Context) error {
+	if e.features["ping"] != "1" {
+		return nil
+	}
+
+	e.ping++
+	if err := e.conn.Send("ping " + strconv.Itoa(e.ping)); err != nil {
+		return err
+	}
+
+	for {
+		line, err := e.conn.ReadLine(ctx)
+		if err != nil {
+			return err
+		}
+		if err := engineError(line); err != nil {
+			return err
+		}
+		if command, args := protocol.CutCommand(line); command == "pong" && args == strconv.Itoa(e.ping) {
+			return nil
+		}
+	}
+}
+
+// Result tells the engine the result of the game with the comment, e.g. "White resigns".
+func (e *Engine) Result(result pgn.Result, comment string) error {
+	return e.conn.Send(fmt.Sprintf("result %s {%s}", result, comment))
+}
+
+// Close quits the engine.
+// The engine subprocess is killed if it doesn't quit in time.
+func (e *Engine) Close() error {
+	return e.conn.Close("quit")
+}
+
+// accept answers the feature of the engine and remembers it if it's accepted.
+func (e *Engine) accept(name, value string) error {
+	if !acceptedFeatures[name] {
+		return e.conn.Send("rejected " + name)
+	}
+
+	e.features[name] = value
+
+	return e.conn.Send("accepted " + name)
+}
+
+// sendLimits sends the limits of the thinking, the thinking output is turned on if Progress is set.
+func (e *Engine) sendLimits(limits Limits) error {
+	commands := []string{"nopost"}
+	if e.Progress != nil {
+		commands[0] = "post"
+	}
+	if limits.Depth > 0 {
+		commands = append(commands, "sd "+strconv.Itoa(limits.Depth))
+	}
+	if limits.MoveTime > 0 {
+		commands = append(commands, "st "+strconv.Itoa(max(int(limits.MoveTime.Seconds()), 1)))
+	}
+	if limits.Time > 0 {
+		commands = append(commands, "time "+strconv.FormatInt(centiseconds(limits.Time), 10))
+	}
+	if limits.OpponentTime > 0 {
+		commands = append(commands, "otim "+strconv.FormatInt(centiseconds(limits.OpponentTime), 10))
+	}
+
+	for _, command := range commands {
+		if err := e.conn.Send(command); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// move makes the move of the engine on the board and puts the engine into the force mode,
+// so it doesn't think on the next move of the opponent until Go is called.
+func (e *Engine) move(notation string, last Thinking) (Result, error) {
+	made, err := makeMove(e.board, notation)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{BestMove: made, Thinking: last}, e.conn.Send("force")
+}
+
+// engineError returns the error reported by the line of the engine output:
+// an illegal move, the resignation or the claimed result of the game.
+func engineError(line string) error {
+	line = strings.TrimSpace(line)
+	switch command, _ := protocol.CutCommand(line); {
+	case strings.HasPrefix(line, "Illegal move"):
+		return fmt.Errorf("%w: %s", ErrIllegalMove, line)
+	case command == "resign":
+		return ErrResigned
+	case pgn.Result(command) == pgn.ResultWinWhite ||
+		pgn.Result(command) == pgn.ResultWinBlack ||
+		pgn.Result(command) == pgn.ResultDraw:
+		return fmt.Errorf("%w: %s", ErrGameOver, line)
+	}
+
+	return nil
+}
+
+// parseFeatures parses the arguments of the feature command, e.g. `ping=1 myname="Engine 1.0" done=1`.
+func parseFeatures(args string) []feature {
+	var features []feature
+	for args = strings.TrimSpace(args); args != ""; args = strings.TrimSpace(args) {
+		name, rest, _ := strings.Cut(args, "=")
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			value, args, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, args, _ = strings.Cut(rest, " ")
+		}
+		features = append(features, feature{name: strings.TrimSpace(name), value: value})
+	}
+
+	return features
+}
+
+// newEngine creates the engine talking over the connection.
+func newEngine(conn *protocol.Conn) *Engine {
+	return &Engine{
+		features: make(map[string]string),
+		board:    standardchess.NewBoard(),
+		conn:     conn,
+	}
+}
+
+func centiseconds(d time.Duration) int64 {
+	return d.Milliseconds() / 10
+}
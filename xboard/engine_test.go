@@ -0,0 +1,253 @@
+package xboard_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elaxer/standardchess/encoding/pgn"
+	"github.com/elaxer/standardchess/engine"
+	"github.com/elaxer/standardchess/xboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedEngine is a stand-in engine answering the commands by the script.
+type scriptedEngine struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+func TestEngine_Init(t *testing.T) {
+	engine, script := newScriptedEngine(t, defaultScript)
+
+	require.NoError(t, engine.Init(context.Background()))
+	require.NoError(t, engine.Ping(context.Background()))
+	assert.Equal(t, "Scripted 1.0", engine.Name())
+	san, _ := engine.Feature("san")
+	assert.Equal(t, "1", san)
+	_, ok := engine.Feature("analyze")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{
+		"xboard",
+		"protover 2",
+		"accepted myname",
+		"accepted san",
+		"rejected analyze",
+		"accepted done",
+		"accepted ping",
+		"accepted done",
+		"ping 1",
+	}, script.received())
+}
+
+func TestEngine_Go(t *testing.T) {
+	engine, script := newScriptedEngine(t, defaultScript)
+	require.NoError(t, engine.Init(context.Background()))
+
+	var thinking []xboard.Thinking
+	engine.Progress = func(t xboard.Thinking) {
+		thinking = append(thinking, t)
+	}
+
+	require.NoError(t, engine.NewGame())
+	require.NoError(t, engine.UserMove("e2e4"))
+	result, err := engine.Go(context.Background(), xboard.Limits{
+		Time:         time.Minute,
+		OpponentTime: 50 * time.Second,
+		Depth:        3,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Nf6", result.BestMove.SAN())
+	assert.Equal(t, xboard.Thinking{Depth: 3, Score: -25, Time: 100 * time.Millisecond, Nodes: 1200, PV: "Nf6 e5"},
+		result.Thinking)
+	assert.Equal(t, []xboard.Thinking{result.Thinking}, thinking)
+	assert.Len(t, engine.Board().MoveHistory(), 2)
+
+	require.NoError(t, engine.Undo())
+	assert.Len(t, engine.Board().MoveHistory(), 1)
+	require.NoError(t, engine.Result(pgn.ResultWinWhite, "Black resigns"))
+	require.NoError(t, engine.Ping(context.Background()))
+
+	commands := script.received()
+	assert.Equal(t, []string{
+		"new", "force", "e4", "post", "sd 3", "time 6000", "otim 5000", "go", "force", "undo",
+		"result 1-0 {Black resigns}", "ping 1",
+	}, commands[len(commands)-12:])
+}
+
+func TestEngine_Go_MoveNow(t *testing.T) {
+	engine, script := newScriptedEngine(t, func(command string, w io.Writer) bool {
+		switch command {
+		case "go":
+		case "?":
+			fmt.Fprintln(w, "move e7e5")
+		default:
+			return defaultScript(command, w)
+		}
+
+		return true
+	})
+	require.NoError(t, engine.Init(context.Background()))
+	require.NoError(t, engine.SetBoard("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	result, err := engine.Go(ctx, xboard.Limits{MoveTime: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, "e5", result.BestMove.SAN())
+	require.NoError(t, engine.Ping(context.Background()))
+
+	commands := script.received()
+	assert.Equal(t, []string{"setboard rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1",
+		"nopost", "st 3600", "go", "?", "force", "ping 1"}, commands[len(commands)-7:])
+}
+
+func TestEngine_Go_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		want   error
+	}{
+		{"resign", "resign", xboard.ErrResigned},
+		{"claim", "0-1 {Black mates}", xboard.ErrGameOver},
+		{"illegal move", "Illegal move: e4", xboard.ErrIllegalMove},
+		{"illegal engine move", "move e2e4", xboard.ErrIllegalMove},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, _ := newScriptedEngine(t, func(command string, w io.Writer) bool {
+				if command == "go" {
+					fmt.Fprintln(w, tt.answer)
+
+					return true
+				}
+
+				return defaultScript(command, w)
+			})
+			require.NoError(t, engine.Init(context.Background()))
+			require.NoError(t, engine.UserMove("e4"))
+
+			_, err := engine.Go(context.Background(), xboard.Limits{})
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestEngine_UserMove_Illegal(t *testing.T) {
+	engine, _ := newScriptedEngine(t, defaultScript)
+
+	assert.ErrorIs(t, engine.UserMove("e5"), xboard.ErrIllegalMove)
+	assert.Empty(t, engine.Board().MoveHistory())
+}
+
+func TestEngine_Exited(t *testing.T) {
+	engine, _ := newScriptedEngine(t, func(string, io.Writer) bool {
+		return false
+	})
+
+	assert.ErrorIs(t, engine.Init(context.Background()), xboard.ErrEngineExited)
+}
+
+func TestEngine_Server(t *testing.T) {
+	commandsReader, commandsWriter := io.Pipe()
+	outputReader, outputWriter := io.Pipe()
+	go func() {
+		_ = xboard.NewServer(engine.New(1<<16)).Run(commandsReader, outputWriter)
+		_ = outputWriter.Close()
+	}()
+
+	client := xboard.NewEngine(outputReader, commandsWriter)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.Init(ctx))
+	assert.Equal(t, "standardchess", client.Name())
+
+	require.NoError(t, client.NewGame())
+	require.NoError(t, client.UserMove("d4"))
+	result, err := client.Go(ctx, xboard.Limits{Depth: 2})
+	require.NoError(t, err)
+	assert.Equal(t, result.BestMove.SAN(), client.Board().MoveHistory()[1].String())
+
+	require.NoError(t, client.UserMove("c4"))
+	_, err = client.Go(ctx, xboard.Limits{MoveTime: time.Second, Depth: 1})
+	require.NoError(t, err)
+	assert.Len(t, client.Board().MoveHistory(), 4)
+}
+
+// defaultScript answers the commands of the tests.
+func defaultScript(command string, w io.Writer) bool {
+	switch {
+	case command == "protover 2":
+		fmt.Fprintln(w, `feature myname="Scripted 1.0" san=1 analyze=1 done=0`)
+		fmt.Fprintln(w, "# loading")
+		fmt.Fprintln(w, "feature ping=1 done=1")
+	case command == "go":
+		fmt.Fprintln(w, "3 -25 10 1200 Nf6 e5")
+		fmt.Fprintln(w, "move Nf6")
+	case strings.HasPrefix(command, "ping "):
+		fmt.Fprintln(w, "pong "+strings.TrimPrefix(command, "ping "))
+	case command == "quit":
+		return false
+	}
+
+	return true
+}
+
+// newScriptedEngine runs the scripted engine connected to the engine by the pipes.
+// The script returns false to make the scripted engine exit.
+func newScriptedEngine(t *testing.T, script func(command string, w io.Writer) bool) (*xboard.Engine, *scriptedEngine) {
+	t.Helper()
+
+	commandsReader, commandsWriter := io.Pipe()
+	outputReader, outputWriter := io.Pipe()
+	t.Cleanup(func() {
+		_ = commandsWriter.Close()
+		_ = outputWriter.Close()
+	})
+
+	scripted := new(scriptedEngine)
+	go func() {
+		scripted.run(commandsReader, outputWriter, script)
+		_ = outputWriter.Close()
+		_, _ = io.Copy(io.Discard, commandsReader)
+	}()
+
+	engine := xboard.NewEngine(outputReader, commandsWriter)
+	t.Cleanup(func() {
+		_ = engine.Close()
+	})
+
+	return engine, scripted
+}
+
+func (s *scriptedEngine) run(r io.Reader, w io.Writer, script func(command string, w io.Writer) bool) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		s.mu.Lock()
+		s.commands = append(s.commands, scanner.Text())
+		s.mu.Unlock()
+
+		if !script(scanner.Text(), w) {
+			return
+		}
+	}
+}
+
+func (s *scriptedEngine) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.commands...)
+}
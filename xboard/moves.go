@@ -0,0 +1,42 @@
+package xboard
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/game"
+)
+
+// makeMove makes the move in SAN or coordinate notation, e.g. "Nf3" or "g1f3", on the board and returns it.
+func makeMove(board standardchess.Board, notation string) (standardchess.Move, error) {
+	legalMoves := standardchess.MoveList(board)
+
+	result, err := standardchess.MakeMovePastDraw(board, notation)
+	if err != nil {
+		return standardchess.Move{}, fmt.Errorf("%w: %s: %w", ErrIllegalMove, notation, err)
+	}
+
+	i := slices.IndexFunc(legalMoves, func(move standardchess.Move) bool {
+		return move.SAN() == result.String()
+	})
+	if i < 0 {
+		_, _ = board.UndoLastMove()
+
+		return standardchess.Move{}, fmt.Errorf("%w: %s", ErrIllegalMove, notation)
+	}
+
+	return legalMoves[i], nil
+}
+
+// gameResult returns the result command of the finished game, e.g. "1-0 {White mates}".
+// The second value is false if the game isn't finished.
+func gameResult(board chess.Board) (string, bool) {
+	outcome, ok := game.OutcomeFromBoard(board)
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s {%s}", outcome.Result, outcome.Reason), true
+}
@@ -0,0 +1,355 @@
+package xboard
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/elaxer/chess"
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/encoding/fen"
+	"github.com/elaxer/standardchess/engine"
+	"github.com/elaxer/standardchess/internal/protocol"
+)
+
+// serverName is the name of the built-in engine reported to the GUI.
+const serverName = "standardchess"
+
+// serverFeatures are the features of the protocol version 2 supported by Server.
+const serverFeatures = `feature myname="` + serverName + `" usermove=1 setboard=1 ping=1 san=0 ` +
+	`sigint=0 sigterm=0 colors=0 reuse=1 analyze=0 variants="normal" done=1`
+
+// defaultMoveTime is the time of a move if the GUI has set neither the time control nor the depth.
+const defaultMoveTime = 2 * time.Second
+
+// serverCommands are the handlers of the commands of the GUI, the other commands are ignored.
+var serverCommands = map[string]func(s *Server, args string){
+	"protover":  (*Server).protover,
+	"new":       (*Server).newGame,
+	"force":     (*Server).force,
+	"go":        (*Server).goCommand,
+	"playother": (*Server).playOther,
+	"usermove":  (*Server).userMove,
+	"undo":      (*Server).undo,
+	"remove":    (*Server).remove,
+	"setboard":  (*Server).setBoard,
+	"result":    (*Server).result,
+	"time":      (*Server).time,
+	// The clock of the opponent doesn't affect the search.
+	"otim":   func(*Server, string) {},
+	"level":  (*Server).level,
+	"st":     (*Server).st,
+	"sd":     (*Server).sd,
+	"post":   func(s *Server, _ string) { s.post.Store(true) },
+	"nopost": func(s *Server, _ string) { s.post.Store(false) },
+	"ping":   func(s *Server, args string) { s.send("pong " + args) },
+	"?":      func(s *Server, _ string) { s.moveNow() },
+}
+
+// Server runs the built-in engine speaking the Chess Engine Communication Protocol with a GUI,
+// e.g. XBoard, WinBoard or Cute Chess. The engine plays on a standardchess board.
+type Server struct {
+	mu  sync.Mutex
+	out io.Writer
+
+	engine      *engine.Engine
+	board       standardchess.Board
+	forceMode   bool
+	engineColor chess.Color
+	post        atomic.Bool
+	search      *serverSearch
+
+	// movesPerSession and increment are the time control set by the level command.
+	movesPerSession int
+	increment       time.Duration
+	// clock is the remaining time of the engine set by the time command, engine.NoClock until it's set.
+	clock    time.Duration
+	moveTime time.Duration
+	depth    int
+}
+
+// serverSearch is the thinking of the engine on its move.
+type serverSearch struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	aborted atomic.Bool
+}
+
+// NewServer creates the server of the engine, the engine plays black in a new game by default.
+func NewServer(e *engine.Engine) *Server {
+	return &Server{
+		engine:      e,
+		board:       protocol.SearchBoard(standardchess.NewBoard()),
+		engineColor: chess.ColorBlack,
+		clock:       engine.NoClock,
+	}
+}
+
+// Run handles the commands of the GUI read from r and writes the answers to w
+// until the quit command or the end of the input.
+func (s *Server) Run(r io.Reader, w io.Writer) error {
+	s.out = w
+	s.engine.Progress = s.thinking
+	defer s.abort()
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		command, args := protocol.CutCommand(scanner.Text())
+		if command == "quit" {
+			return nil
+		}
+
+		if handle, ok := serverCommands[command]; ok {
+			handle(s, args)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (s *Server) protover(string) {
+	s.send(serverFeatures)
+}
+
+// newGame resets the board, the engine plays black.
+func (s *Server) newGame(string) {
+	s.abort()
+	s.engine.Clear()
+	s.board = protocol.SearchBoard(standardchess.NewBoard())
+	s.forceMode = false
+	s.engineColor = chess.ColorBlack
+	s.depth = 0
+}
+
+// force stops the engine from playing: the moves of both sides are made by the GUI.
+func (s *Server) force(string) {
+	s.abort()
+	s.forceMode = true
+}
+
+// goCommand makes the engine play the side to move and think on its move.
+func (s *Server) goCommand(string) {
+	s.abort()
+	s.forceMode = false
+	s.engineColor = s.board.Turn()
+	s.think()
+}
+
+// playOther makes the engine play the side which isn't to move.
+func (s *Server) playOther(string) {
+	s.abort()
+	s.forceMode = false
+	s.engineColor = !s.board.Turn()
+}
+
+// userMove makes the move of the opponent, the engine thinks on its move then.
+func (s *Server) userMove(args string) {
+	s.abort()
+	if _, err := makeMove(s.board, args); err != nil {
+		s.send("Illegal move: " + args)
+
+		return
+	}
+
+	if result, ok := gameResult(s.board); ok {
+		s.send(result)
+
+		return
+	}
+	s.think()
+}
+
+// undo takes back the last move.
+func (s *Server) undo(string) {
+	s.abort()
+	_, _ = s.board.UndoLastMove()
+}
+
+// remove takes back the last moves of both sides.
+func (s *Server) remove(string) {
+	s.undo("")
+	s.undo("")
+}
+
+func (s *Server) setBoard(args string) {
+	s.abort()
+	board, err := fen.Decode(args)
+	if err != nil {
+		s.send("tellusererror Illegal position")
+
+		return
+	}
+	s.board = protocol.SearchBoard(board)
+}
+
+// result stops the engine from playing when the game is over.
+func (s *Server) result(string) {
+	s.force("")
+}
+
+// time sets the time on the clock of the engine in centiseconds.
+func (s *Server) time(args string) {
+	if cs, err := strconv.Atoi(args); err == nil {
+		s.clock = time.Duration(cs) * 10 * time.Millisecond
+	}
+}
+
+// level sets the time control, e.g. "40 5 0" for 40 moves in 5 minutes or "0 2:30 1" for 2.5 minutes plus 1 second.
+func (s *Server) level(args string) {
+	fields := strings.Fields(args)
+	if len(fields) != 3 {
+		return
+	}
+
+	s.movesPerSession, _ = strconv.Atoi(fields[0])
+	s.clock = parseMinutes(fields[1])
+	if increment, err := strconv.ParseFloat(fields[2], 64); err == nil {
+		s.increment = time.Duration(increment * float64(time.Second))
+	}
+	s.moveTime = 0
+}
+
+// st sets the exact time of a move in seconds.
+func (s *Server) st(args string) {
+	if seconds, err := strconv.Atoi(args); err == nil {
+		s.moveTime = time.Duration(seconds) * time.Second
+	}
+}
+
+// sd sets the maximum depth of the search.
+func (s *Server) sd(args string) {
+	s.depth, _ = strconv.Atoi(args)
+}
+
+// think starts the search of the move if it's the turn of the engine.
+// The move is made and sent when the search is finished unless the search is aborted.
+func (s *Server) think() {
+	if s.forceMode || s.board.Turn() != s.engineColor {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	current := &serverSearch{cancel: cancel, done: make(chan struct{})}
+	s.search = current
+
+	board, limits := s.board, s.limits()
+	go func() {
+		defer close(current.done)
+		defer cancel()
+
+		result, err := s.engine.Search(ctx, board, limits)
+		if current.aborted.Load() {
+			return
+		}
+		if err != nil {
+			if result, ok := gameResult(board); ok {
+				s.send(result)
+			}
+
+			return
+		}
+
+		if _, err := board.MakeMove(result.BestMove.UCI()); err != nil {
+			return
+		}
+		s.send("move " + result.BestMove.UCI())
+		if result, ok := gameResult(board); ok {
+			s.send(result)
+		}
+	}()
+}
+
+// limits returns the limits of the search by the time control.
+// The search takes defaultMoveTime if there is no time control and no depth.
+func (s *Server) limits() engine.Limits {
+	limits := engine.Limits{Depth: s.depth, Time: s.moveTime}
+	if limits.Time > 0 {
+		return limits
+	}
+
+	movesToGo := 0
+	if s.movesPerSession > 0 {
+		played := len(s.board.MoveHistory()) / 2
+		movesToGo = s.movesPerSession - played%s.movesPerSession
+	}
+	limits.Time = engine.MoveTime(s.clock, s.increment, movesToGo)
+	if limits.Time == 0 && limits.Depth == 0 {
+		limits.Time = defaultMoveTime
+	}
+
+	return limits
+}
+
+// moveNow stops the search, the best move found by then is made.
+func (s *Server) moveNow() {
+	if s.search != nil {
+		s.search.cancel()
+	}
+}
+
+// abort stops the search without making the move and waits for it.
+func (s *Server) abort() {
+	if s.search == nil {
+		return
+	}
+
+	s.search.aborted.Store(true)
+	s.search.cancel()
+	<-s.search.done
+	s.search = nil
+}
+
+// thinking sends the result of the completed iteration of the search if the thinking output is on.
+func (s *Server) thinking(result engine.Result) {
+	if !s.post.Load() {
+		return
+	}
+
+	sans := make([]string, 0, len(result.PV))
+	for _, move := range result.PV {
+		sans = append(sans, move.SAN())
+	}
+
+	s.send(Thinking{
+		Depth: result.Depth,
+		Score: thinkingScore(result.Score),
+		Time:  result.Duration,
+		Nodes: result.Nodes,
+		PV:    strings.Join(sans, " "),
+	}.String())
+}
+
+func (s *Server) send(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fmt.Fprintln(s.out, line)
+}
+
+// thinkingScore returns the score of the search in the thinking output.
+func thinkingScore(score engine.Score) int {
+	mate, ok := score.Mate()
+	switch {
+	case !ok:
+		return int(score)
+	case mate > 0:
+		return MateScore + mate
+	default:
+		return -MateScore + mate
+	}
+}
+
+// parseMinutes parses the base time of the level command in minutes, e.g. "5" or "2:30".
+func parseMinutes(value string) time.Duration {
+	minutes, seconds, _ := strings.Cut(value, ":")
+	m, _ := strconv.Atoi(minutes)
+	sec, _ := strconv.Atoi(seconds)
+
+	return time.Duration(m)*time.Minute + time.Duration(sec)*time.Second
+}
@@ -0,0 +1,234 @@
+package xboard_test
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elaxer/standardchess"
+	"github.com/elaxer/standardchess/engine"
+	"github.com/elaxer/standardchess/xboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTimeout is the time the GUI waits for an answer of the server.
+const testTimeout = 10 * time.Second
+
+// testGUI sends the commands to the server and reads its answers.
+type testGUI struct {
+	t     *testing.T
+	w     *io.PipeWriter
+	lines chan string
+	done  chan error
+}
+
+func TestServer_Protover(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("xboard")
+	gui.send("protover 2")
+	assert.Equal(t, `feature myname="standardchess" usermove=1 setboard=1 ping=1 san=0 `+
+		`sigint=0 sigterm=0 colors=0 reuse=1 analyze=0 variants="normal" done=1`, gui.next())
+
+	gui.send("ping 7")
+	assert.Equal(t, "pong 7", gui.next())
+
+	gui.send("quit")
+	assert.NoError(t, gui.wait())
+}
+
+func TestServer_UserMove(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("new")
+	gui.send("sd 2")
+	gui.send("usermove e2e4")
+
+	board, err := standardchess.NewBoardFromMoves([]string{"e4"})
+	require.NoError(t, err)
+	assertLegalMove(t, board, gui.next())
+}
+
+func TestServer_Go(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("new")
+	gui.send("force")
+	gui.send("usermove e2e4")
+	gui.send("usermove e7e5")
+	gui.send("level 40 5 0")
+	gui.send("time 1000")
+	gui.send("otim 1000")
+	gui.send("go")
+
+	board, err := standardchess.NewBoardFromMoves([]string{"e4", "e5"})
+	require.NoError(t, err)
+	assertLegalMove(t, board, gui.next())
+}
+
+func TestServer_Go_NoTimeLeft(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("new")
+	gui.send("force")
+	gui.send("level 40 5 0")
+	gui.send("time 0")
+	gui.send("go")
+
+	assertLegalMove(t, standardchess.NewBoard(), gui.next())
+}
+
+func TestServer_Go_NoTimeControl(t *testing.T) {
+	gui := newTestGUI(t)
+
+	// The engine thinks for the default time of a move.
+	gui.send("new")
+	gui.send("force")
+	gui.send("go")
+
+	assertLegalMove(t, standardchess.NewBoard(), gui.next())
+}
+
+func TestServer_Post(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("post")
+	gui.send("setboard 6k1/5ppp/8/8/8/8/8/R3K3 w - - 0 1")
+	gui.send("sd 2")
+	gui.send("go")
+
+	thinking, ok := xboard.ParseThinking(gui.next())
+	require.True(t, ok)
+	assert.Equal(t, 1, thinking.Depth)
+	assert.Equal(t, xboard.MateScore+1, thinking.Score)
+	assert.Equal(t, "Ra8#", thinking.PV)
+
+	assert.Equal(t, "move a1a8", gui.next())
+	assert.Equal(t, "1-0 {White mates}", gui.next())
+}
+
+func TestServer_MoveNow(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("new")
+	gui.send("force")
+	gui.send("st 1000")
+	gui.send("go")
+	time.Sleep(50 * time.Millisecond)
+	gui.send("?")
+
+	assertLegalMove(t, standardchess.NewBoard(), gui.next())
+}
+
+func TestServer_IllegalMove(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("usermove e2e5")
+	assert.Equal(t, "Illegal move: e2e5", gui.next())
+
+	gui.send("setboard 8/8/8")
+	assert.Equal(t, "tellusererror Illegal position", gui.next())
+}
+
+func TestServer_Undo(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("force")
+	gui.send("usermove e2e4")
+	gui.send("undo")
+	gui.send("usermove e2e4")
+	gui.send("usermove e7e5")
+	gui.send("remove")
+	gui.send("usermove e2e4")
+	gui.send("ping 1")
+	assert.Equal(t, "pong 1", gui.next())
+}
+
+func TestServer_Result(t *testing.T) {
+	gui := newTestGUI(t)
+
+	gui.send("new")
+	gui.send("result 1-0 {Black resigns}")
+	gui.send("usermove e2e4")
+	gui.send("ping 1")
+	assert.Equal(t, "pong 1", gui.next())
+}
+
+func assertLegalMove(t *testing.T, board standardchess.Board, line string) {
+	t.Helper()
+
+	move, ok := strings.CutPrefix(line, "move ")
+	require.True(t, ok, line)
+
+	_, err := board.MakeMove(move)
+	assert.NoError(t, err)
+}
+
+// newTestGUI runs the server connected to the GUI by the pipes.
+func newTestGUI(t *testing.T) *testGUI {
+	t.Helper()
+
+	commandsReader, commandsWriter := io.Pipe()
+	outputReader, outputWriter := io.Pipe()
+
+	gui := &testGUI{t: t, w: commandsWriter, lines: make(chan string, 100), done: make(chan error, 1)}
+	go func() {
+		gui.done <- xboard.NewServer(engine.New(1<<16)).Run(commandsReader, outputWriter)
+		_ = outputWriter.Close()
+	}()
+	go func() {
+		defer close(gui.lines)
+
+		scanner := bufio.NewScanner(outputReader)
+		for scanner.Scan() {
+			gui.lines <- scanner.Text()
+		}
+	}()
+	t.Cleanup(func() {
+		_ = commandsWriter.Close()
+		for range gui.lines {
+		}
+	})
+
+	return gui
+}
+
+func (g *testGUI) send(command string) {
+	g.t.Helper()
+
+	_, err := io.WriteString(g.w, command+"\n")
+	require.NoError(g.t, err)
+}
+
+// next returns the next answer of the server.
+func (g *testGUI) next() string {
+	g.t.Helper()
+
+	select {
+	case line, ok := <-g.lines:
+		require.True(g.t, ok, "the server has exited")
+
+		return line
+	case <-time.After(testTimeout):
+		require.FailNow(g.t, "no answer of the server")
+
+		return ""
+	}
+}
+
+// wait waits for the server to exit.
+func (g *testGUI) wait() error {
+	g.t.Helper()
+
+	select {
+	case err := <-g.done:
+		return err
+	case <-time.After(testTimeout):
+		require.FailNow(g.t, "the server hasn't exited")
+
+		return nil
+	}
+}
@@ -0,0 +1,70 @@
+package xboard
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MateScore is the score of the mate in 0 moves in the thinking output,
+// the score of the mate in N moves is MateScore + N and the score of being mated in N moves is -MateScore - N.
+const MateScore = 100000
+
+// Thinking is the thinking output of the engine, e.g. "9 156 1084 48000 Nf3 Nc6 Nc3 Nf6".
+type Thinking struct {
+	// Depth is the depth of the search in plies.
+	Depth int
+	// Score is the evaluation of the position in centipawns from the point of view of the engine.
+	Score int
+	// Time is the time spent on the search.
+	Time time.Duration
+	// Nodes is the number of the searched positions.
+	Nodes int
+	// PV is the principal variation as it's sent by the engine, usually in SAN.
+	PV string
+}
+
+// ParseThinking parses the thinking output line of the engine.
+// The second value is false if the line isn't a thinking output.
+func ParseThinking(line string) (Thinking, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 {
+		return Thinking{}, false
+	}
+
+	var numbers [4]int
+	for i := range numbers {
+		number, err := strconv.Atoi(strings.TrimRight(fields[i], ".&"))
+		if err != nil {
+			return Thinking{}, false
+		}
+		numbers[i] = number
+	}
+
+	return Thinking{
+		Depth: numbers[0],
+		Score: numbers[1],
+		Time:  time.Duration(numbers[2]) * 10 * time.Millisecond,
+		Nodes: numbers[3],
+		PV:    strings.Join(fields[4:], " "),
+	}, true
+}
+
+// IsMate reports whether the score is a mate.
+func (t Thinking) IsMate() bool {
+	return t.Score >= MateScore || t.Score <= -MateScore
+}
+
+func (t Thinking) String() string {
+	line := strings.Join([]string{
+		strconv.Itoa(t.Depth),
+		strconv.Itoa(t.Score),
+		strconv.FormatInt(centiseconds(t.Time), 10),
+		strconv.Itoa(t.Nodes),
+	}, " ")
+	if t.PV != "" {
+		line += " " + t.PV
+	}
+
+	return line
+}